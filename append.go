@@ -14,7 +14,8 @@ func (q *stream) Append(v ...interface{}) Stream {
 }
 
 func (q *stream) appendOne(v interface{}) *stream {
-	return newStream(newCtx(q.ctx), q.expectElemTyp, q.iter, func(next iterator) iterator {
+	ctx := q.derivedCtx()
+	return newStream(ctx, q.expectElemTyp, q.upstream(ctx), func(next iterator) iterator {
 		var flag int32
 		return func() (reflect.Value, bool) {
 			if flag == 0 {
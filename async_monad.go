@@ -0,0 +1,159 @@
+package fp
+
+import (
+	gocontext "context"
+	"reflect"
+	"sync/atomic"
+)
+
+var stdContextType = reflect.TypeOf((*gocontext.Context)(nil)).Elem()
+
+// Async start fn in a goroutine, fn is kind of func(&optional context.Context) (type1,&optional error/bool),
+// the context is canceled when a sibling fails in every Zip waiting for it
+func Async(fn interface{}) Monad {
+	var sigs []funcSig
+	for _, in := range [][]reflect.Type{nil, types(stdContextType)} {
+		sigs = append(sigs, sig(in, nil), sig(in, nil, boolType), sig(in, nil, errType))
+	}
+	assertFunc("Async", fn, sigs...)
+	fnVal := toErrMonadFunc(fn)
+	return startAsync(reflect.FuncOf(nil, outTypes(fnVal.Type()), false), func(ctx gocontext.Context) []reflect.Value {
+		if fnVal.Type().NumIn() == 1 {
+			return fnVal.Call([]reflect.Value{reflect.ValueOf(ctx)})
+		}
+		return fnVal.Call(nil)
+	})
+}
+
+/* future holds result of computation running in background */
+type future struct {
+	done   chan struct{}
+	out    []reflect.Value
+	cancel func()
+	/* waiters is number of zips waiting for the future */
+	waiters int32
+}
+
+/* acquire register a zip waiting for f */
+func (f *future) acquire() { atomic.AddInt32(&f.waiters, 1) }
+
+/* release unregister a zip which got result of f */
+func (f *future) release() { atomic.AddInt32(&f.waiters, -1) }
+
+/* abandon unregister a failed zip, f is canceled once no zip waits for it */
+func (f *future) abandon() {
+	if atomic.AddInt32(&f.waiters, -1) == 0 {
+		f.cancel()
+	}
+}
+
+type asyncMonad struct {
+	/* fn of errorMonad waits the future */
+	errorMonad
+	f *future
+}
+
+/* startAsync run fn in goroutine, typ is kind of func() (any,bool,error) */
+func startAsync(typ reflect.Type, run func(gocontext.Context) []reflect.Value) asyncMonad {
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	f := &future{done: make(chan struct{}), cancel: cancel}
+	go func() {
+		defer close(f.done)
+		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				f.out = []reflect.Value{reflect.Zero(typ.Out(0)), reflect.ValueOf(false), reflect.ValueOf(newPanicError(r, reflect.Value{}))}
+			}
+		}()
+		f.out = run(ctx)
+	}()
+	return asyncMonad{
+		errorMonad: newErrorMonad(reflect.MakeFunc(typ, func([]reflect.Value) []reflect.Value {
+			<-f.done
+			return f.out
+		})),
+		f: f,
+	}
+}
+
+func (am asyncMonad) Async() Monad { return am }
+
+func (am asyncMonad) Await(ctx gocontext.Context) Value {
+	select {
+	case <-am.f.done:
+		return am.Val()
+	case <-ctx.Done():
+		return Value{err: ctx.Err()}
+	}
+}
+
+func (am asyncMonad) Zip(fn interface{}, others ...Monad) Monad {
+	assertMapper("Monad.Zip", fn, append([]reflect.Type{am.fn.Type().Out(0)}, make([]reflect.Type, len(others))...)...)
+	return zipAsync(fn, append([]Monad{am}, others...))
+}
+
+/* zipAsync wait all monads concurrently, the first error or empty monad fails the zip and async monads are abandoned,
+ * so that each of them is canceled unless another zip still waits for it
+ */
+func zipAsync(fn interface{}, monads []Monad) Monad {
+	fnVal := toErrMonadFunc(fn)
+	outTyp := fnVal.Type().Out(0)
+	var futures []*future
+	for _, m := range monads {
+		if a, ok := m.(asyncMonad); ok {
+			a.f.acquire()
+			futures = append(futures, a.f)
+		}
+	}
+	return startAsync(reflect.FuncOf(nil, outTypes(fnVal.Type()), false), func(ctx gocontext.Context) []reflect.Value {
+		type result struct {
+			i   int
+			v   interface{}
+			ok  bool
+			err error
+		}
+		fail := func(err error) []reflect.Value {
+			for _, f := range futures {
+				f.abandon()
+			}
+			return []reflect.Value{reflect.Zero(outTyp), reflect.ValueOf(false), errValue(err)}
+		}
+		results := make(chan result, len(monads))
+		for i, m := range monads {
+			go func(i int, m Monad) {
+				v, ok, err := m.fnContainer()()
+				results <- result{i: i, v: v, ok: ok, err: err}
+			}(i, m)
+		}
+		input := make([]reflect.Value, len(monads))
+		for range monads {
+			select {
+			case r := <-results:
+				if r.err != nil || !r.ok {
+					return fail(r.err)
+				}
+				input[r.i] = convertTo(reflect.ValueOf(r.v), fnVal.Type().In(r.i))
+			case <-ctx.Done():
+				return fail(ctx.Err())
+			}
+		}
+		for _, f := range futures {
+			f.release()
+		}
+		return fnVal.Call(input)
+	})
+}
+
+func (em errorMonad) Async() Monad {
+	return startAsync(em.fn.Type(), func(gocontext.Context) []reflect.Value {
+		return em.fn.Call(nil)
+	})
+}
+
+func (em errorMonad) Await(ctx gocontext.Context) Value {
+	return em.Async().Await(ctx)
+}
+
+func (m nilMonad) Async() Monad { return m }
+
+func (m nilMonad) Await(gocontext.Context) Value { return m.Val() }
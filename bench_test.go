@@ -0,0 +1,97 @@
+package fp
+
+import (
+	"strconv"
+	"testing"
+)
+
+/* benchInt has no fast path, so it measures reflect.Value.Call with the same work */
+type benchInt int
+
+func benchInts(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+func benchSlowInts(n int) []benchInt {
+	out := make([]benchInt, n)
+	for i := range out {
+		out[i] = benchInt(i)
+	}
+	return out
+}
+
+func BenchmarkMapFastPath(b *testing.B) {
+	data := benchInts(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		StreamOf(data).Map(func(i int) int { return i * 2 }).Run()
+	}
+}
+
+func BenchmarkMapReflectPath(b *testing.B) {
+	data := benchSlowInts(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		StreamOf(data).Map(func(i benchInt) benchInt { return i * 2 }).Run()
+	}
+}
+
+func BenchmarkFilterFastPath(b *testing.B) {
+	data := benchInts(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		StreamOf(data).Filter(func(i int) bool { return i%2 == 0 }).Run()
+	}
+}
+
+func BenchmarkFilterReflectPath(b *testing.B) {
+	data := benchSlowInts(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		StreamOf(data).Filter(func(i benchInt) bool { return i%2 == 0 }).Run()
+	}
+}
+
+func BenchmarkMapStringFastPath(b *testing.B) {
+	data := make([]string, 1000)
+	for i := range data {
+		data[i] = strconv.Itoa(i)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		StreamOf(data).Map(func(s string) string { return s + "x" }).Filter(func(s string) bool { return len(s) > 2 }).Run()
+	}
+}
+
+/* long chains of element-wise ops are fused into one loop, allocs/op should not grow with each element per op */
+func BenchmarkLongChainFastPath(b *testing.B) {
+	data := benchInts(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := StreamOf(data)
+		for j := 0; j < 4; j++ {
+			s = s.Map(func(i int) int { return i + 1 }).
+				Filter(func(i int) bool { return i%7 != 0 }).
+				Reject(func(i int) bool { return i%11 == 0 })
+		}
+		s.TakeWhile(func(i int) bool { return i < 2000 }).Run()
+	}
+}
+
+func BenchmarkLongChainReflectPath(b *testing.B) {
+	data := benchSlowInts(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := StreamOf(data)
+		for j := 0; j < 4; j++ {
+			s = s.Map(func(i benchInt) benchInt { return i + 1 }).
+				Filter(func(i benchInt) bool { return i%7 != 0 }).
+				Reject(func(i benchInt) bool { return i%11 == 0 })
+		}
+		s.Foreach(func(benchInt) {}).TakeWhile(func(i benchInt) bool { return i < 2000 }).Run()
+	}
+}
@@ -0,0 +1,76 @@
+package fp
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Cache record elements as they are first pulled, every stream derived from the cached stream replays from the start
+func (q *stream) Cache() Stream {
+	return q.cache(0)
+}
+
+// CacheLimit cache at most size latest elements, replaying evicted elements would fail with ErrCacheEvicted
+func (q *stream) CacheLimit(size int) Stream {
+	if size < 1 {
+		panic("cache size should be greater than 0")
+	}
+	return q.cache(size)
+}
+
+func (q *stream) cache(limit int) Stream {
+	ctx := newDetachedCtx(q.ctx)
+	buf := &cacheBuffer{upstream: q, limit: limit}
+	s := newStream(ctx, q.expectElemTyp, buf.cursor(ctx))
+	/* every derived stream replays with its own cursor, lifecycle and error */
+	s.fork = buf.cursor
+	return s
+}
+
+/* rewind restart iterator of cached stream from the start, so every terminal op of it sees all elements */
+func (q *stream) rewind() {
+	if q.fork != nil {
+		q.setIter(q.fork(q.ctx))
+	}
+}
+
+type cacheBuffer struct {
+	mu       sync.Mutex
+	upstream *stream
+	vals     []reflect.Value
+	/* base is index of vals[0] */
+	base  int
+	limit int
+	done  bool
+}
+
+func (b *cacheBuffer) cursor(ctx context) iterator {
+	var i int
+	return func() (reflect.Value, bool) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if i < b.base {
+			ctx.SetErr(ErrCacheEvicted)
+			return reflect.Value{}, false
+		}
+		for i-b.base >= len(b.vals) {
+			if b.done {
+				return reflect.Value{}, false
+			}
+			val, ok := b.upstream.iter()
+			if !ok {
+				/* everything is cached, upstream could be released */
+				b.done = true
+				b.upstream.finish(true)
+				return reflect.Value{}, false
+			}
+			b.vals = append(b.vals, val)
+			if b.limit > 0 && len(b.vals) > b.limit {
+				b.vals = b.vals[1:]
+				b.base++
+			}
+		}
+		i++
+		return b.vals[i-1-b.base], true
+	}
+}
@@ -0,0 +1,123 @@
+package fp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+/* compareValue compare a and b of typ by natural ordering, return -1, 0 or 1
+ * order by: time.Time, method Compare(T) int, method Less(T) bool, then kind:
+ * numbers(NaN is the smallest), strings, bools(false first), []byte, slices/arrays element-wise,
+ * structs field-wise, pointers(nil first) by pointed value, interfaces by dynamic value
+ */
+func compareValue(typ reflect.Type, a, b reflect.Value) int {
+	if a.CanInterface() && b.CanInterface() {
+		if c, ok := compareByMethod(typ, a, b); ok {
+			return c
+		}
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return compareOrdered(a.String() < b.String(), a.String() > b.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int() < b.Int(), a.Int() > b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(a.Uint() < b.Uint(), a.Uint() > b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareFloat(a.Float(), b.Float())
+	case reflect.Bool:
+		return compareOrdered(!a.Bool() && b.Bool(), a.Bool() && !b.Bool())
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return bytes.Compare(a.Bytes(), b.Bytes())
+		}
+		return compareSeq(typ.Elem(), a, b)
+	case reflect.Array:
+		return compareSeq(typ.Elem(), a, b)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if c := compareValue(typ.Field(i).Type, a.Field(i), b.Field(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			return compareOrdered(a.IsNil() && !b.IsNil(), !a.IsNil() && b.IsNil())
+		}
+		return compareValue(typ.Elem(), a.Elem(), b.Elem())
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return compareOrdered(a.IsNil() && !b.IsNil(), !a.IsNil() && b.IsNil())
+		}
+		if ta, tb := a.Elem().Type(), b.Elem().Type(); ta != tb {
+			return compareOrdered(ta.String() < tb.String(), ta.String() > tb.String())
+		}
+		return compareValue(a.Elem().Type(), a.Elem(), b.Elem())
+	default:
+		if !a.CanInterface() || !b.CanInterface() {
+			/* unexported fields, fmt could still print them */
+			s1, s2 := fmt.Sprint(a), fmt.Sprint(b)
+			return compareOrdered(s1 < s2, s1 > s2)
+		}
+		if reflect.DeepEqual(a.Interface(), b.Interface()) {
+			return 0
+		}
+		s1, s2 := fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface())
+		return compareOrdered(s1 < s2, s1 > s2)
+	}
+}
+
+/* compareByMethod compare time.Time and types with method Compare(T) int or Less(T) bool */
+func compareByMethod(typ reflect.Type, a, b reflect.Value) (int, bool) {
+	if typ == timeType {
+		ta, tb := a.Interface().(time.Time), b.Interface().(time.Time)
+		return compareOrdered(ta.Before(tb), ta.After(tb)), true
+	}
+	if m, ok := typ.MethodByName("Compare"); ok && isMethodOf(m, typ, intType) {
+		c := m.Func.Call([]reflect.Value{a, b})[0].Int()
+		return compareOrdered(c < 0, c > 0), true
+	}
+	if m, ok := typ.MethodByName("Less"); ok && isMethodOf(m, typ, boolType) {
+		less := func(x, y reflect.Value) bool { return m.Func.Call([]reflect.Value{x, y})[0].Bool() }
+		return compareOrdered(less(a, b), less(b, a)), true
+	}
+	return 0, false
+}
+
+func compareOrdered(less, greater bool) int {
+	if less {
+		return -1
+	} else if greater {
+		return 1
+	}
+	return 0
+}
+
+/* compareFloat NaN equals NaN and is less than any number */
+func compareFloat(a, b float64) int {
+	if an, bn := math.IsNaN(a), math.IsNaN(b); an || bn {
+		return compareOrdered(an && !bn, !an && bn)
+	}
+	return compareOrdered(a < b, a > b)
+}
+
+/* compareSeq compare slices or arrays element-wise, shorter one is less if it is prefix of the other */
+func compareSeq(elemTyp reflect.Type, a, b reflect.Value) int {
+	for i := 0; i < a.Len() && i < b.Len(); i++ {
+		if c := compareValue(elemTyp, a.Index(i), b.Index(i)); c != 0 {
+			return c
+		}
+	}
+	return compareOrdered(a.Len() < b.Len(), a.Len() > b.Len())
+}
+
+/* isMethodOf tell if m is func(typ) out of typ */
+func isMethodOf(m reflect.Method, typ, out reflect.Type) bool {
+	return m.Type.NumIn() == 2 && m.Type.In(1) == typ && m.Type.NumOut() == 1 && m.Type.Out(0) == out
+}
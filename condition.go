@@ -1,6 +1,9 @@
 package fp
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 /* when function should be func(type) bool
  * then/else function should be func(type) (any1,any2...)
@@ -54,8 +57,17 @@ func (b ifBuilder) Else(fn interface{}) interface{} {
 			panic("else function is nil")
 		}
 	}
-	return reflect.MakeFunc(reflect.TypeOf(fn), func(in []reflect.Value) []reflect.Value {
-		for i, fn := range conditionList() {
+	typ := reflect.TypeOf(fn)
+	if typ == nil || typ.Kind() != reflect.Func {
+		panic(fmt.Sprintf("fp: Else expects func, got %v", typ))
+	}
+	conds := resolvePredicates(conditionList(), typ)
+	for i, cond := range conds {
+		assertFunc("When", cond, sig(inTypes(typ), boolType))
+		assertFunc("Then", b.thenList[i], sig(inTypes(typ), outTypes(typ)...))
+	}
+	return reflect.MakeFunc(typ, func(in []reflect.Value) []reflect.Value {
+		for i, fn := range conds {
 			if reflect.ValueOf(fn).Call(in)[0].Bool() {
 				return reflect.ValueOf(b.thenList[i]).Call(in)
 			}
@@ -97,6 +109,12 @@ func Or(fn1, fn2 interface{}, other ...interface{}) iCondition {
 
 func Not(fn interface{}) iCondition {
 	fn = resolveConditionFunc(fn)
+	if p, ok := fn.(Predicate); ok {
+		return condition{fn: reflect.ValueOf(Predicate{compile: func(typ reflect.Type) func(reflect.Value) bool {
+			test := p.compile(typ)
+			return func(v reflect.Value) bool { return !test(v) }
+		}})}
+	}
 	return condition{
 		fn: reflect.MakeFunc(reflect.TypeOf(fn), func(in []reflect.Value) []reflect.Value {
 			return []reflect.Value{reflect.ValueOf(!reflect.ValueOf(fn).Call(in)[0].Bool())}
@@ -131,35 +149,69 @@ type condition struct {
 	fn reflect.Value
 }
 
-func (c condition) Out() interface{}   { return c.fn.Interface() }
-func (c condition) To(ptr interface{}) { reflect.ValueOf(ptr).Elem().Set(c.fn) }
+func (c condition) Out() interface{} { return c.fn.Interface() }
+func (c condition) To(ptr interface{}) {
+	dst := reflect.ValueOf(ptr).Elem()
+	dst.Set(reflect.ValueOf(resolvePredicates([]interface{}{c.Out()}, dst.Type())[0]))
+}
 
 func (c condition) And(fns ...interface{}) iCondition {
-	list := append([]interface{}{c.fn.Interface()}, fns...)
-	getList := resolveConditionFuncList(list...)
-	return condition{
-		fn: reflect.MakeFunc(c.fn.Type(), func(in []reflect.Value) []reflect.Value {
-			for _, fn := range getList() {
-				if out := reflect.ValueOf(fn).Call(in); !out[0].Bool() {
-					return out
-				}
-			}
-			return []reflect.Value{reflect.ValueOf(true)}
-		}),
-	}
+	return compose(append([]interface{}{c.fn.Interface()}, fns...), false)
 }
 
 func (c condition) Or(fns ...interface{}) iCondition {
-	list := append([]interface{}{c.fn.Interface()}, fns...)
-	getList := resolveConditionFuncList(list...)
+	return compose(append([]interface{}{c.fn.Interface()}, fns...), true)
+}
+
+/* compose make condition which returns stop once any function of list does, else returns !stop.
+ * Predicates of list are resolved against the first typed function, the condition is a Predicate if all of them are
+ */
+func compose(list []interface{}, stop bool) iCondition {
+	fns := resolveConditionFuncList(list...)()
+	var typ reflect.Type
+	for _, fn := range fns {
+		if _, ok := fn.(Predicate); !ok {
+			typ = reflect.TypeOf(fn)
+			break
+		}
+	}
+	if typ == nil {
+		return condition{fn: reflect.ValueOf(Predicate{compile: func(in reflect.Type) func(reflect.Value) bool {
+			tests := make([]func(reflect.Value) bool, len(fns))
+			for i, fn := range fns {
+				tests[i] = fn.(Predicate).compile(in)
+			}
+			return func(v reflect.Value) bool {
+				for _, test := range tests {
+					if test(v) == stop {
+						return stop
+					}
+				}
+				return !stop
+			}
+		}})}
+	}
+	fns = resolvePredicates(fns, typ)
 	return condition{
-		fn: reflect.MakeFunc(c.fn.Type(), func(in []reflect.Value) []reflect.Value {
-			for _, fn := range getList() {
-				if out := reflect.ValueOf(fn).Call(in); out[0].Bool() {
+		fn: reflect.MakeFunc(typ, func(in []reflect.Value) []reflect.Value {
+			for _, fn := range fns {
+				if out := reflect.ValueOf(fn).Call(in); out[0].Bool() == stop {
 					return out
 				}
 			}
-			return []reflect.Value{reflect.ValueOf(false)}
+			return []reflect.Value{reflect.ValueOf(!stop)}
 		}),
 	}
 }
+
+/* resolvePredicates resolve Predicates of fns against input of function type typ */
+func resolvePredicates(fns []interface{}, typ reflect.Type) []interface{} {
+	if typ == nil || typ.Kind() != reflect.Func || typ.NumIn() != 1 {
+		return fns
+	}
+	out := make([]interface{}, len(fns))
+	for i, fn := range fns {
+		out[i] = resolvePredicate(fn, typ.In(0))
+	}
+	return out
+}
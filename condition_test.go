@@ -1,8 +1,10 @@
 package fp
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -238,3 +240,167 @@ func (suite *FPIfTestSuite) TestDefaultCondition() {
 		When(between1And10).Then(func(i int) (int, string) { return i, "between 1 and 10" }).Else(nil)
 	})
 }
+
+type matchCreated struct{ ID int }
+
+type matchDeleted struct{ ID int }
+
+func (suite *FPIfTestSuite) TestMatch() {
+	describe := Match().Case(func(e *matchCreated) string {
+		return fmt.Sprintf("created %d", e.ID)
+	}).Case(func(e matchDeleted) (string, error) {
+		return fmt.Sprintf("deleted %d", e.ID), nil
+	}).Case(func(e fmt.Stringer) string {
+		return e.String()
+	}).Build().(func(interface{}) (string, error))
+
+	out, err := describe(&matchCreated{ID: 1})
+	suite.NoError(err)
+	suite.Equal("created 1", out)
+	out, _ = describe(matchDeleted{ID: 2})
+	suite.Equal("deleted 2", out)
+	out, _ = describe(time.Second)
+	suite.Equal("1s", out)
+
+	_, err = describe(3)
+	suite.True(errors.Is(err, ErrNoMatch))
+	_, err = describe(nil)
+	suite.True(errors.Is(err, ErrNoMatch))
+
+	events := []interface{}{&matchCreated{ID: 1}, matchDeleted{ID: 2}}
+	suite.Equal([]string{"created 1", "deleted 2"}, StreamOf(events).Map(describe).Strings())
+	suite.Error(StreamOf([]interface{}{1}).Map(describe).Error())
+}
+
+func (suite *FPIfTestSuite) TestMatchDefault() {
+	fn := Match().Case(func(i int) int {
+		return i * 2
+	}).Default(func(v interface{}) int {
+		return -1
+	}).(func(interface{}) (int, error))
+	out, err := fn(2)
+	suite.NoError(err)
+	suite.Equal(4, out)
+	out, err = fn("a")
+	suite.NoError(err)
+	suite.Equal(-1, out)
+
+	suite.Panics(func() {
+		Match().Case(func(i int) int { return i }).Case(func(s string) string { return s })
+	})
+	suite.Panics(func() {
+		Match().Case(func(i int) int { return i }).Default(func(i int) int { return i })
+	})
+	suite.Panics(func() { Match().Build() })
+}
+
+func (suite *FPIfTestSuite) TestPredicates() {
+	suite.Equal([]int{1, 3}, StreamOf([]int{1, 2, 3}).Filter(In(1, 3)).Ints())
+	suite.Equal([]int{2, 3}, StreamOf([]int{1, 2, 3, 4}).Filter(Between(2, 3)).Ints())
+	var floats []float64
+	StreamOf([]float64{1.5, 2.5}).Filter(GreaterThan(2.0)).ToSlice(&floats)
+	suite.Equal([]float64{2.5}, floats)
+	suite.Equal([]string{"a"}, StreamOf([]string{"a", "b"}).Filter(LessThan("b")).Strings())
+	suite.Equal([]string{"ab"}, StreamOf([]string{"ab", "ba"}).Filter(HasPrefix("a")).Strings())
+	suite.Equal([]string{"ba"}, StreamOf([]string{"ab", "ba"}).Filter(HasSuffix("a")).Strings())
+	suite.Equal([]string{"abc"}, StreamOf([]string{"abc", "bd"}).Filter(Contains("bc")).Strings())
+	suite.Equal([]string{"a1"}, StreamOf([]string{"a1", "b"}).Filter(MatchRegexp(`\d$`)).Strings())
+	suite.Equal([]int{1, 2}, StreamOf([]int{0, 1, 2}).Reject(IsZero()).Ints())
+	suite.Equal(1, StreamOf([]*int{nil, new(int)}).Reject(IsNil()).Size())
+
+	fn := And(GreaterThan(1), Not(In(3)), LessThan(5)).Out().(func(int) bool)
+	suite.True(fn(2))
+	suite.False(fn(3))
+	suite.False(fn(5))
+
+	suite.Panics(func() { In() })
+	suite.Panics(func() { Between(1, "a") })
+	suite.Panics(func() { MatchRegexp("(") })
+}
+
+func (suite *FPIfTestSuite) TestPredicatesResolvedByElementType() {
+	statuses := []matchStatus{"active", "banned", ""}
+	var out []matchStatus
+	StreamOf(statuses).Filter(HasPrefix("act")).ToSlice(&out)
+	suite.Equal([]matchStatus{"active"}, out)
+	StreamOf(statuses).Filter(Or(HasSuffix("ned"), MatchRegexp("^act")).Out()).ToSlice(&out)
+	suite.Equal([]matchStatus{"active", "banned"}, out)
+	StreamOf(statuses).Reject(IsZero()).Filter(Not(Contains("tiv")).Out()).ToSlice(&out)
+	suite.Equal([]matchStatus{"banned"}, out)
+
+	fn := And(IsZero(), func(i int) bool { return i >= 0 }).Out().(func(int) bool)
+	suite.True(fn(0))
+	suite.False(fn(1))
+	suite.Equal([]int{1}, StreamOf([]int{0, 1, -1}).Filter(And(Not(IsZero()), GreaterThan(0)).Out()).Ints())
+	var pred func(string) bool
+	Or(IsZero(), HasPrefix("a")).To(&pred)
+	suite.True(pred("ab"))
+	suite.False(pred("b"))
+	upper := When(HasPrefix("a")).Then(func(s string) string { return s + "!" }).Else(nil).(func(string) string)
+	suite.Equal("a!", upper("a"))
+	suite.Equal("b", upper("b"))
+
+	var users []*predicateUser
+	StreamOf([]*predicateUser{{Name: "ann", Status: "active"}, {Name: "bob"}}).Filter(Field("Status", HasPrefix("act"))).ToSlice(&users)
+	suite.Len(users, 1)
+	var values []interface{}
+	StreamOf([]interface{}{nil, 0, 1}).Reject(IsZero()).ToSlice(&values)
+	suite.Equal([]interface{}{1}, values)
+
+	suite.PanicsWithValue("fp: HasPrefix expects element of string kind, got int", func() {
+		StreamOf([]int{1}).Filter(HasPrefix("a"))
+	})
+}
+
+type predicateUser struct {
+	Name   string
+	Status matchStatus
+	Age    int
+}
+
+type matchStatus string
+
+func (suite *FPIfTestSuite) TestFieldPredicate() {
+	users := []*predicateUser{
+		{Name: "a", Status: "active", Age: 20},
+		{Name: "b", Status: "banned", Age: 30},
+		{Name: "c", Status: "active", Age: 40},
+	}
+	var out []*predicateUser
+	StreamOf(users).Filter(Field("Status", Equal("active"))).ToSlice(&out)
+	suite.Len(out, 2)
+	StreamOf(users).Filter(And(Field("Status", Equal("active")), Field("Age", GreaterThan(30))).Out()).ToSlice(&out)
+	suite.Len(out, 1)
+	suite.Equal("c", out[0].Name)
+
+	rows := []map[string]interface{}{{"id": 1}, {"id": 2}, {}}
+	suite.Equal(1, StreamOf(rows).Filter(Field("id", In(2))).Size())
+
+	suite.Panics(func() { StreamOf(users).Filter(Field("Unknown", Equal(1))).Run() })
+	suite.Panics(func() { StreamOf(users).Filter(Field("Age", Equal("a"))).Run() })
+}
+
+func (suite *FPIfTestSuite) TestFieldPredicateValidatedOnCall() {
+	users := []*predicateUser{{Name: "a", Age: 20}, {Name: "b", Age: 30}}
+	var calls int
+	suite.PanicsWithValue("fp: Field Unknown of *fp.predicateUser: no exported field Unknown in fp.predicateUser", func() {
+		StreamOf(users).Foreach(func(*predicateUser) { calls++ }).Filter(Field("Unknown", Equal(1)))
+	})
+	suite.Zero(calls)
+	/* elements of interface type are validated by dynamic type */
+	suite.Panics(func() {
+		StreamOf([]interface{}{users[0]}).Filter(Field("Unknown", Equal(1))).Run()
+	})
+	suite.Equal(1, StreamOf([]interface{}{users[0], users[1], nil}).Filter(Field("Age", GreaterThan(20))).Size())
+	suite.PanicsWithValue("fp: Field Age of *fp.predicateUser is int, predicate expects string", func() {
+		StreamOf(users).Filter(Field("Age", Equal("a"))).Run()
+	})
+
+	type nested struct {
+		User  *predicateUser
+		Attrs map[string]interface{}
+	}
+	rows := []nested{{User: users[0], Attrs: map[string]interface{}{"k": nil}}, {Attrs: map[string]interface{}{"k": 1}}}
+	suite.Equal(1, StreamOf(rows).Filter(Field("User.Age", Equal(20))).Size())
+	suite.Equal(1, StreamOf(rows).Filter(Field("Attrs.k", Equal(1))).Size())
+}
@@ -1,9 +1,6 @@
 package fp
 
-import (
-	"fmt"
-	"reflect"
-)
+import "reflect"
 
 func (q *stream) Contains(e interface{}) (yes bool) {
 	var eq func(reflect.Value) bool
@@ -23,24 +20,39 @@ func (q *stream) Contains(e interface{}) (yes bool) {
 		eq = func(v reflect.Value) bool { return v.Bool() == t }
 	case reflect.Float32, reflect.Float64:
 		t := val.Float()
-		eq = func(v reflect.Value) bool { return v.Float() == t }
+		eq = func(v reflect.Value) bool { return compareFloat(v.Float(), t) == 0 }
 	default:
-		eq = func(v reflect.Value) bool { return reflect.DeepEqual(v.Interface(), e) }
+		typ := val.Type()
+		if _, ok := compareByMethod(typ, val, val); ok && typ == q.expectElemTyp {
+			/* time.Time and types with Compare/Less method are equal by the method, like Sort */
+			eq = func(v reflect.Value) bool { c, _ := compareByMethod(typ, v, val); return c == 0 }
+		} else {
+			eq = func(v reflect.Value) bool { return reflect.DeepEqual(v.Interface(), e) }
+		}
 	}
 
-	q.iter = repeatableIter(q.iter, func(v reflect.Value) bool {
+	q.rewind()
+	q.setIter(repeatableIter(q.iter, func(v reflect.Value) bool {
 		yes = eq(v)
 		return !yes
-	})
+	}))
+	q.finish(!yes)
 	return
 }
 
 func (q *stream) ContainsBy(eqfn interface{}) (yes bool) {
+	eqfn = resolvePredicate(eqfn, q.expectElemTyp)
+	assertPredicate("ContainsBy", eqfn, q.expectElemTyp)
 	fnval := reflect.ValueOf(eqfn)
-	q.iter = repeatableIter(q.iter, func(v reflect.Value) bool {
+	var val reflect.Value
+	defer recoverErr(q.ctx, &val)
+	q.rewind()
+	q.setIter(repeatableIter(q.iter, func(v reflect.Value) bool {
+		val = v
 		yes = fnval.Call([]reflect.Value{v})[0].Bool()
 		return !yes
-	})
+	}))
+	q.finish(!yes)
 	return
 }
 
@@ -70,40 +82,5 @@ func repeatableIter(iter iterator, f func(reflect.Value) bool) iterator {
 }
 
 func (q *stream) compare(a, b reflect.Value) int {
-	switch q.expectElemTyp.Kind() {
-	case reflect.String:
-		if a.String() < b.String() {
-			return -1
-		} else if a.String() > b.String() {
-			return 1
-		}
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if a.Int() < b.Int() {
-			return -1
-		} else if a.Int() > b.Int() {
-			return 1
-		}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		if a.Uint() < b.Uint() {
-			return -1
-		} else if a.Uint() > b.Uint() {
-			return 1
-		}
-	case reflect.Bool:
-		if !a.Bool() && b.Bool() {
-			return -1
-		} else if a.Bool() && !b.Bool() {
-			return 1
-		}
-	default:
-		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
-			s1, s2 := fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface())
-			if s1 < s2 {
-				return -1
-			} else if s1 > s2 {
-				return 1
-			}
-		}
-	}
-	return 0
+	return compareValue(q.expectElemTyp, a, b)
 }
@@ -3,10 +3,18 @@ package fp
 type context interface {
 	SetErr(err error)
 	Err() error
+	// Safe report whether panics of user functions should be recovered
+	Safe() bool
+	// hooks is lifecycle shared by whole stream
+	hooks() *lifecycle
 }
 type _context struct {
 	parent context
 	err    error
+	safe   bool
+	lc     *lifecycle
+	/* detached context has its own lifecycle */
+	detached bool
 }
 
 func (ctx *_context) SetErr(err error) {
@@ -23,9 +31,37 @@ func (ctx *_context) Err() error {
 	return nil
 }
 
+func (ctx *_context) Safe() bool {
+	if ctx.safe {
+		return true
+	}
+	return ctx.parent != nil && ctx.parent.Safe()
+}
+
+func (ctx *_context) hooks() *lifecycle {
+	if ctx.parent != nil && !ctx.detached {
+		return ctx.parent.hooks()
+	}
+	if ctx.lc == nil {
+		ctx.lc = &lifecycle{}
+	}
+	return ctx.lc
+}
+
 func newCtx(parent context) context {
 	if parent == nil {
 		parent = &_context{}
 	}
 	return &_context{parent: parent}
 }
+
+func newDetachedCtx(parent context) context {
+	return &_context{parent: parent, detached: true}
+}
+
+func newSafeCtx(parent context) context {
+	if parent == nil {
+		parent = &_context{}
+	}
+	return &_context{parent: parent, safe: true}
+}
@@ -1,9 +1,9 @@
 package fp
 
 import (
-	"reflect"
-
+	"fmt"
 	"math"
+	"reflect"
 )
 
 // NewCounter create int source with elements 0, 1, 2...count
@@ -40,6 +40,24 @@ func (cs *counterSource) Next() (reflect.Value, bool) {
 	return reflect.Value{}, false
 }
 
+func (cs *counterSource) Len() int {
+	if cs.from < cs.to {
+		return cs.to - cs.from
+	}
+	return 0
+}
+
+func (cs *counterSource) advance(n int) { cs.from += clamp(n, 0, cs.Len()) }
+
+func (cs *counterSource) At(i int) reflect.Value { return reflect.ValueOf(cs.from + i) }
+
+func (cs *counterSource) Slice(from, to int) RandomAccessSource {
+	if from < 0 || from > to || to > cs.Len() {
+		panic(fmt.Sprintf("fp: slice [%d:%d] out of range with length %d", from, to, cs.Len()))
+	}
+	return &counterSource{from: cs.from + from, to: cs.from + to}
+}
+
 // NaturalNumbers stream with uint64 elements 0, 1, 2...
 func NaturalNumbers() Stream {
 	return StreamOfSource(&naturalNumSource{})
@@ -30,12 +30,13 @@ func StreamOfCursor(c Cursor, mapfn interface{}) Stream {
 		}
 		return failedRes
 	})
+	s := StreamOf(fn.Interface())
+	/* close cursor with stream, e.g. *sql.Rows */
+	closeWith(s.(*stream).ctx, c)
 	if nmap, bmap, ok := convertBooleanMap(mapfn); ok {
-		return StreamOf(fn.Interface()).
-			Map(_wrapCursorMap(nmap)).Map(bmap)
+		return s.Map(_wrapCursorMap(nmap)).Map(bmap)
 	}
-	return StreamOf(fn.Interface()).
-		Map(_wrapCursorMap(mapfn))
+	return s.Map(_wrapCursorMap(mapfn))
 }
 
 func _makeCursorMapArgsWithErr(argTypes []reflect.Type) func(func(...interface{}) error) []interface{} {
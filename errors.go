@@ -0,0 +1,86 @@
+package fp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCacheEvicted replay elements evicted from cache
+var ErrCacheEvicted = errors.New("fp: cached element was evicted")
+
+// ErrTimeout map function exceeded its Timeout
+var ErrTimeout = errors.New("fp: timeout")
+
+// ErrNoMatch input of Match function matches no case
+var ErrNoMatch = errors.New("fp: no case matched")
+
+// ElementError error caused by the element at Index of stage
+type ElementError struct {
+	Stage   string
+	Index   int
+	Element interface{}
+	Err     error
+}
+
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("fp: %s element #%d (%v): %v", e.Stage, e.Index, e.Element, e.Err)
+}
+
+func (e *ElementError) Unwrap() error { return e.Err }
+
+// MultiError all errors collected by stream, works with errors.Is/errors.As
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("fp: %d errors occurred: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+func (e *MultiError) Unwrap() []error { return e.Errors }
+
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *MultiError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// PanicError panic of user function recovered in safe mode
+type PanicError struct {
+	// Value recovered from panic
+	Value interface{}
+	// Element being processed, nil if unknown
+	Element interface{}
+	Stack   []byte
+}
+
+func (e *PanicError) Error() string {
+	if e.Element == nil {
+		return fmt.Sprintf("fp: panic: %v", e.Value)
+	}
+	return fmt.Sprintf("fp: panic on element %v: %v", e.Element, e.Value)
+}
+
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
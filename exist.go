@@ -6,16 +6,17 @@ import (
 )
 
 func (q *stream) IsEmpty() bool {
+	q.rewind()
 	old := q.iter
 	v, ok := q.iter()
 	if ok {
 		var flag int32
-		q.iter = func() (reflect.Value, bool) {
+		q.setIter(func() (reflect.Value, bool) {
 			if atomic.CompareAndSwapInt32(&flag, 0, 1) {
 				return v, true
 			}
 			return old()
-		}
+		})
 	}
 	return !ok
 }
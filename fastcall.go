@@ -0,0 +1,94 @@
+package fp
+
+import "reflect"
+
+/* fast paths: common callback signatures are called directly instead of reflect.Value.Call,
+ * others reuse one argument slice since Call copies it
+ */
+
+/* predicateOf return caller of predicate fn */
+func predicateOf(fn interface{}) func(reflect.Value) bool {
+	switch f := fn.(type) {
+	case func(int) bool:
+		return func(v reflect.Value) bool { return f(int(direct(v).Int())) }
+	case func(int64) bool:
+		return func(v reflect.Value) bool { return f(direct(v).Int()) }
+	case func(string) bool:
+		return func(v reflect.Value) bool { return f(direct(v).String()) }
+	case func(float64) bool:
+		return func(v reflect.Value) bool { return f(direct(v).Float()) }
+	case func(interface{}) bool:
+		return func(v reflect.Value) bool { return f(v.Interface()) }
+	}
+	fnVal := reflect.ValueOf(fn)
+	args := make([]reflect.Value, 1)
+	return func(v reflect.Value) bool {
+		args[0] = v
+		return fnVal.Call(args)[0].Bool()
+	}
+}
+
+/* mapperOf return caller of fn with one input and one output */
+func mapperOf(fn interface{}) func(reflect.Value) reflect.Value {
+	switch f := fn.(type) {
+	case func(int) int:
+		return func(v reflect.Value) reflect.Value { return reflect.ValueOf(f(int(direct(v).Int()))) }
+	case func(int) string:
+		return func(v reflect.Value) reflect.Value { return reflect.ValueOf(f(int(direct(v).Int()))) }
+	case func(int64) int64:
+		return func(v reflect.Value) reflect.Value { return reflect.ValueOf(f(direct(v).Int())) }
+	case func(string) string:
+		return func(v reflect.Value) reflect.Value { return reflect.ValueOf(f(direct(v).String())) }
+	case func(string) int:
+		return func(v reflect.Value) reflect.Value { return reflect.ValueOf(f(direct(v).String())) }
+	case func(float64) float64:
+		return func(v reflect.Value) reflect.Value { return reflect.ValueOf(f(direct(v).Float())) }
+	case func(interface{}) interface{}:
+		return func(v reflect.Value) reflect.Value {
+			/* keep static type interface{} like reflect.Value.Call does, nil result is valid */
+			out := f(v.Interface())
+			return reflect.ValueOf(&out).Elem()
+		}
+	}
+	fnVal := reflect.ValueOf(fn)
+	args := make([]reflect.Value, 1)
+	return func(v reflect.Value) reflect.Value {
+		args[0] = v
+		return fnVal.Call(args)[0]
+	}
+}
+
+/* slotMapperOf like mapperOf, but results of basic types are written into one reused slot,
+ * so the result is only valid until next call, borrowed reports whether the slot is used
+ */
+func slotMapperOf(fn interface{}) (call func(reflect.Value) reflect.Value, borrowed bool) {
+	switch f := fn.(type) {
+	case func(int) int:
+		slot := reflect.New(intType).Elem()
+		return func(v reflect.Value) reflect.Value { slot.SetInt(int64(f(int(direct(v).Int())))); return slot }, true
+	case func(int) string:
+		slot := reflect.New(stringType).Elem()
+		return func(v reflect.Value) reflect.Value { slot.SetString(f(int(direct(v).Int()))); return slot }, true
+	case func(int64) int64:
+		slot := reflect.New(int64Type).Elem()
+		return func(v reflect.Value) reflect.Value { slot.SetInt(f(direct(v).Int())); return slot }, true
+	case func(string) string:
+		slot := reflect.New(stringType).Elem()
+		return func(v reflect.Value) reflect.Value { slot.SetString(f(direct(v).String())); return slot }, true
+	case func(string) int:
+		slot := reflect.New(intType).Elem()
+		return func(v reflect.Value) reflect.Value { slot.SetInt(int64(f(direct(v).String()))); return slot }, true
+	case func(float64) float64:
+		slot := reflect.New(float64Type).Elem()
+		return func(v reflect.Value) reflect.Value { slot.SetFloat(f(direct(v).Float())); return slot }, true
+	}
+	return mapperOf(fn), false
+}
+
+/* direct unwrap value of interface kind */
+func direct(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface {
+		return v.Elem()
+	}
+	return v
+}
@@ -3,17 +3,38 @@ package fp
 import "reflect"
 
 func (q *stream) Filter(fn interface{}) Stream {
+	fn = resolvePredicate(fn, q.expectElemTyp)
+	assertPredicate("Filter", fn, q.expectElemTyp)
+	return q.filter(predicateOf(fn))
+}
+
+func (q *stream) FilterOrDeadLetter(fn interface{}, dl interface{}) Stream {
+	assertFunc("FilterOrDeadLetter", fn, sig(types(q.expectElemTyp), boolType, errType))
+	deadLetter := deadLetterOf("FilterOrDeadLetter", dl, q.expectElemTyp)
 	fnVal := reflect.ValueOf(fn)
-	return newStream(newCtx(q.ctx), q.expectElemTyp, q.iter, func(next iterator) iterator {
-		return func() (reflect.Value, bool) {
-			for {
-				if val, ok := next(); !ok {
-					break
-				} else if fnVal.Call([]reflect.Value{val})[0].Bool() {
-					return val, true
-				}
-			}
-			return reflect.Value{}, false
+	/* Call copies args, so one slice is reused for all elements */
+	args := make([]reflect.Value, 1)
+	var index int
+	return q.fuse(q.derivedCtx(), q.expectElemTyp, stage{run: func(val reflect.Value) (reflect.Value, stageResult) {
+		index++
+		args[0] = val
+		out := fnVal.Call(args)
+		if err := out[1].Interface(); !isNilObject(err) {
+			deadLetter(val, &ElementError{Stage: "Filter", Index: index - 1, Element: val.Interface(), Err: err.(error)})
+			return val, skipElem
+		}
+		if out[0].Bool() {
+			return val, emitElem
+		}
+		return val, skipElem
+	}, passThrough: true})
+}
+
+func (q *stream) filter(pred func(reflect.Value) bool) Stream {
+	return q.fuse(q.derivedCtx(), q.expectElemTyp, stage{run: func(val reflect.Value) (reflect.Value, stageResult) {
+		if pred(val) {
+			return val, emitElem
 		}
-	})
+		return val, skipElem
+	}, passThrough: true})
 }
@@ -4,10 +4,12 @@ import "reflect"
 
 func (q *stream) First() (f Value) {
 	f.typ = q.expectElemTyp
-	q.iter = repeatableIter(q.iter, func(v reflect.Value) bool {
+	q.rewind()
+	q.setIter(repeatableIter(q.iter, func(v reflect.Value) bool {
 		f.val = v
 		return false
-	})
+	}))
+	q.finish(!f.val.IsValid())
 	f.err = q.ctx.Err()
 	return f
 }
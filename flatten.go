@@ -12,29 +12,34 @@ func (q *stream) Flatten() Stream {
 
 	var elemType reflect.Type
 	_makeIter := makeIter
+	ctx2 := q.derivedCtx()
+	upstream := q.upstream(ctx2)
 	if q.expectElemTyp == streamType {
-		iter := q.iter
+		iter := upstream
 		for {
 			v, ok := iter()
 			if !ok {
 				/* no inner stream found and cause we couldn't guess inner type */
 				/* just return nil stream */
-				return newNilStream()
+				return nilStreamOf(ctx2)
 			}
-			/* we should find first non-NilStream element */
-			if isNilStream(v.Interface().(Stream)) {
+			/* we should find first non-NilStream element, streams known to be empty without pulling contribute nothing */
+			if inner := v.Interface().(Stream); isNilStream(inner) || isKnownEmpty(inner) {
 				continue
 			}
 			/* gotcha */
 			elemType = v.Interface().(Stream).ToSource().ElemType()
 			/* recover q's iter */
 			var flag int32
-			q.iter = func() (reflect.Value, bool) {
+			upstream = func() (reflect.Value, bool) {
 				if atomic.CompareAndSwapInt32(&flag, 0, 1) {
 					return v, true
 				}
 				return iter()
 			}
+			if q.fork == nil {
+				q.setIter(upstream)
+			}
 			break
 		}
 		_makeIter = func(_ context, v reflect.Value) (reflect.Type, iterator) {
@@ -43,8 +48,7 @@ func (q *stream) Flatten() Stream {
 	} else {
 		elemType = q.expectElemTyp.Elem()
 	}
-	ctx2 := newCtx(q.ctx)
-	return newStream(ctx2, elemType, q.iter, func(outernext iterator) iterator {
+	return newStream(ctx2, elemType, upstream, func(outernext iterator) iterator {
 		var innernext iterator
 		var inner reflect.Value
 		return func() (item reflect.Value, ok bool) {
@@ -72,3 +76,13 @@ func (q *stream) Flatten() Stream {
 		}
 	})
 }
+
+/* isKnownEmpty tell if s reads from a sized source with nothing left, it never pulls s */
+func isKnownEmpty(s Stream) bool {
+	q, ok := s.(*stream)
+	if !ok {
+		return false
+	}
+	src, ok := q.src.(SizedSource)
+	return ok && src.Len() == 0
+}
@@ -3,20 +3,18 @@ package fp
 import "reflect"
 
 func (q *stream) Foreach(fn interface{}) Stream {
+	assertFunc("Foreach", fn, sig(types(q.expectElemTyp)), sig(types(q.expectElemTyp, intType)))
 	fnval := reflect.ValueOf(fn)
 	withIndex := fnval.Type().NumIn() == 2
-	return newStream(newCtx(q.ctx), q.expectElemTyp, q.iter, func(next iterator) iterator {
-		var i int
-		return func() (val reflect.Value, ok bool) {
-			if val, ok = next(); ok {
-				if withIndex {
-					fnval.Call([]reflect.Value{val, reflect.ValueOf(i)})
-					i++
-				} else {
-					fnval.Call([]reflect.Value{val})
-				}
-			}
-			return
+	var i int
+	args := make([]reflect.Value, fnval.Type().NumIn())
+	return q.fuse(q.derivedCtx(), q.expectElemTyp, stage{run: func(val reflect.Value) (reflect.Value, stageResult) {
+		args[0] = val
+		if withIndex {
+			args[1] = reflect.ValueOf(i)
+			i++
 		}
-	})
+		fnval.Call(args)
+		return val, emitElem
+	}, passThrough: true})
 }
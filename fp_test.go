@@ -8,6 +8,8 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -81,6 +83,236 @@ func (suite *TestFPTestSuite) TestFlatMapErr() {
 	suite.Error(err)
 }
 
+func (suite *TestFPTestSuite) TestMapSkipErrors() {
+	var out []int
+	err := StreamOf([]string{"1", "a", "3"}).Map(strconv.Atoi, OnError(SkipErrors)).ToSlice(&out)
+	suite.NoError(err)
+	suite.Equal([]int{1, 3}, out)
+}
+
+func (suite *TestFPTestSuite) TestMapCollectErrors() {
+	var out []int
+	err := StreamOf([]string{"1", "a", "3", "b"}).Map(strconv.Atoi, OnError(CollectErrors)).ToSlice(&out)
+	suite.Equal([]int{1, 3}, out)
+	suite.Error(err)
+
+	var multi *MultiError
+	suite.True(errors.As(err, &multi))
+	suite.Len(multi.Errors, 2)
+	var elemErr *ElementError
+	suite.True(errors.As(multi.Errors[1], &elemErr))
+	suite.Equal("Map", elemErr.Stage)
+	suite.Equal(3, elemErr.Index)
+	suite.Equal("b", elemErr.Element)
+
+	var numErr *strconv.NumError
+	suite.True(errors.As(err, &numErr))
+	suite.True(errors.Is(err, strconv.ErrSyntax))
+
+	err = StreamOf([]string{"1", "3"}).Map(strconv.Atoi, OnError(CollectErrors)).ToSlice(&out)
+	suite.NoError(err)
+	suite.Equal([]int{1, 3}, out)
+
+	/* errors seen so far are reported when stream finishes early */
+	var reported error
+	err = StreamOf([]string{"1", "a", "3", "b"}).Map(strconv.Atoi, OnError(CollectErrors)).
+		OnError(func(err error) { reported = err }).Take(2).ToSlice(&out)
+	suite.Equal([]int{1, 3}, out)
+	suite.True(errors.As(err, &multi))
+	suite.Len(multi.Errors, 1)
+	suite.Equal(err, reported)
+
+	first := StreamOf([]string{"a", "2", "b"}).Map(strconv.Atoi, OnError(CollectErrors)).First()
+	suite.True(errors.As(first.Err(), &multi))
+	suite.Len(multi.Errors, 1)
+}
+
+func (suite *TestFPTestSuite) TestMapOrDeadLetter() {
+	var bad []string
+	var errs []error
+	out := StreamOf([]string{"1", "a", "3", "b"}).MapOrDeadLetter(strconv.Atoi, func(s string, err error) {
+		bad = append(bad, s)
+		errs = append(errs, err)
+	}).Ints()
+	suite.Equal([]int{1, 3}, out)
+	suite.Equal([]string{"a", "b"}, bad)
+	suite.Len(errs, 2)
+
+	var letters []*ElementError
+	var res []int
+	err := StreamOf([]string{"1", "a", "3"}).MapOrDeadLetter(strconv.Atoi, func(e *ElementError) {
+		letters = append(letters, e)
+	}).ToSlice(&res)
+	suite.NoError(err)
+	suite.Equal([]int{1, 3}, res)
+	suite.Len(letters, 1)
+	suite.Equal("a", letters[0].Element)
+	suite.Equal("Map", letters[0].Stage)
+	suite.Equal(1, letters[0].Index)
+	suite.True(errors.Is(letters[0], strconv.ErrSyntax))
+
+	/* dead letter function is validated when operator is called */
+	suite.Panics(func() { StreamOf([]string{"a"}).MapOrDeadLetter(strconv.Atoi, func(i int, err error) {}) })
+	suite.Panics(func() { StreamOf([]string{"a"}).MapOrDeadLetter(strconv.Atoi, nil) })
+	suite.Panics(func() { StreamOf([]string{"a"}).Map(strconv.Atoi, OnDeadLetter(func(string) {})) })
+}
+
+func (suite *TestFPTestSuite) TestFilterOrDeadLetter() {
+	var letters []*ElementError
+	isEven := func(s string) (bool, error) {
+		i, err := strconv.Atoi(s)
+		return i%2 == 0, err
+	}
+	out := StreamOf([]string{"1", "a", "2", "4"}).FilterOrDeadLetter(isEven, func(e *ElementError) {
+		letters = append(letters, e)
+	}).Strings()
+	suite.Equal([]string{"2", "4"}, out)
+	suite.Len(letters, 1)
+	suite.Equal("Filter", letters[0].Stage)
+	suite.Equal(1, letters[0].Index)
+	suite.Equal("a", letters[0].Element)
+
+	var bad []string
+	suite.Equal(1, StreamOf([]string{"b", "2"}).FilterOrDeadLetter(isEven, func(s string, err error) {
+		bad = append(bad, s)
+	}).Size())
+	suite.Equal([]string{"b"}, bad)
+
+	suite.Panics(func() { StreamOf([]int{1}).FilterOrDeadLetter(isEven, func(e *ElementError) {}) })
+	suite.Panics(func() { StreamOf([]string{"a"}).FilterOrDeadLetter(isEven, func(int, error) {}) })
+}
+
+/* fakeClock record waits, its timer fires immediately when fire is set, never otherwise */
+type fakeClock struct {
+	fire  bool
+	mu    sync.Mutex
+	waits []time.Duration
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	if c.fire {
+		ch <- time.Time{}
+	}
+	return ch
+}
+
+func (suite *TestFPTestSuite) TestMapRetry() {
+	clock := &fakeClock{fire: true}
+	fail := errors.New("transient")
+	calls := map[int]int{}
+	out := StreamOf([]int{1, 2}).Map(func(i int) (int, error) {
+		calls[i]++
+		if calls[i] < 3 {
+			return 0, fail
+		}
+		return i * 10, nil
+	}, Retry(3, ExponentialBackoff(time.Second, 3*time.Second)), WithClock(clock)).Ints()
+	suite.Equal([]int{10, 20}, out)
+	suite.Equal(map[int]int{1: 3, 2: 3}, calls)
+	suite.Equal([]time.Duration{time.Second, 2 * time.Second, time.Second, 2 * time.Second}, clock.waits)
+
+	calls = map[int]int{}
+	err := StreamOf([]int{1}).Map(func(i int) (int, error) {
+		calls[i]++
+		return 0, fail
+	}, Retry(2, nil), WithClock(clock)).Error()
+	suite.Equal(fail, err)
+	suite.Equal(3, calls[1])
+
+	calls = map[int]int{}
+	err = StreamOf([]int{1}).Map(func(i int) (int, error) {
+		calls[i]++
+		return 0, fail
+	}, Retry(2, nil), RetryIf(func(err error) bool { return err != fail })).Error()
+	suite.Equal(fail, err)
+	suite.Equal(1, calls[1])
+
+	suite.Panics(func() {
+		StreamOf([]int{1}).Map(func(i int) int { return i }, Retry(1, nil))
+	})
+}
+
+func (suite *TestFPTestSuite) TestMapTimeout() {
+	block := make(chan struct{})
+	defer close(block)
+	var out []int
+	err := StreamOf([]int{1, 2}).Map(func(i int) (int, error) {
+		if i == 2 {
+			<-block
+		}
+		return i, nil
+	}, Timeout(time.Second), OnError(CollectErrors), WithClock(&fakeClock{fire: true})).ToSlice(&out)
+	suite.True(errors.Is(err, ErrTimeout))
+	suite.Empty(out)
+
+	out = StreamOf([]int{1, 2}).Map(func(i int) (int, error) {
+		return i, nil
+	}, Timeout(time.Second), WithClock(&fakeClock{})).Ints()
+	suite.Equal([]int{1, 2}, out)
+
+	suite.Panics(func() {
+		StreamOf([]int{1}).Map(func(i int) (int, error) { panic("boom") }, Timeout(time.Second), WithClock(&fakeClock{})).Run()
+	})
+}
+
+func (suite *TestFPTestSuite) TestBackoff() {
+	exp := ExponentialBackoff(time.Millisecond, 5*time.Millisecond)
+	suite.Equal([]time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 5 * time.Millisecond}, []time.Duration{exp(0), exp(1), exp(2), exp(3)})
+	suite.Equal(time.Second, ConstantBackoff(time.Second)(10))
+	jitter := ConstantBackoff(time.Second).Jitter()
+	for i := 0; i < 100; i++ {
+		d := jitter(i)
+		suite.True(d >= time.Second/2 && d <= time.Second)
+	}
+}
+
+func (suite *TestFPTestSuite) TestSafeMode() {
+	var out []int
+	err := StreamOf([]int{1, 2, 0, 4}).SafeMode().Map(func(i int) int {
+		return 4 / i
+	}).ToSlice(&out)
+	suite.Equal([]int{4, 2}, out)
+	var perr *PanicError
+	suite.True(errors.As(err, &perr))
+	suite.Equal(0, perr.Element)
+	suite.NotEmpty(perr.Stack)
+
+	err = StreamOf([]int{1, 2, 0, 4}).Filter(func(i int) bool {
+		return 4/i > 1
+	}).SafeMode().Error()
+	suite.True(errors.As(err, &perr))
+
+	err = StreamOf([]int{2, 1}).SafeMode().SortBy(func(a, b int) bool {
+		panic("bad compare")
+	}).First().Err()
+	suite.True(errors.As(err, &perr))
+	suite.Equal("bad compare", perr.Value)
+
+	err = StreamOf([]int{2, 1}).SafeMode().Reduce(0, func(a, b int) int {
+		panic("bad reduce")
+	}).Err()
+	suite.True(errors.As(err, &perr))
+	suite.Equal(2, perr.Element)
+
+	suite.Panics(func() {
+		StreamOf([]int{0}).Map(func(i int) int { return 4 / i }).Run()
+	})
+}
+
+func (suite *TestFPTestSuite) TestKVSafeMode() {
+	var out map[string]int
+	err := KVStreamOf(map[string]int{"a": 1}).SafeMode().Map(func(k string, v int) (string, int) {
+		panic(errors.New("kv panic"))
+	}).To(&out)
+	suite.Error(err)
+	suite.Equal("kv panic", errors.Unwrap(err).Error())
+	suite.Len(out, 0)
+}
+
 func (suite *TestFPTestSuite) TestErrPassing() {
 	gerr := func(c bool) error {
 		if c {
@@ -542,6 +774,475 @@ func (suite *TestFPTestSuite) TestUniqBy() {
 	suite.ElementsMatch([]int{1, 2}, out)
 }
 
+type hashPoint struct {
+	X, Y int
+	Tags []string
+}
+
+type caseInsensitive []string
+
+func (c caseInsensitive) Hash() uint64 {
+	var h uint64
+	for _, s := range c {
+		h = h*31 + uint64(len(s))
+	}
+	return h
+}
+
+type hashNode struct{ ID int }
+
+func (n *hashNode) Hash() uint64 { return uint64(n.ID) }
+
+func (suite *TestFPTestSuite) TestHasherPointerKeepsIdentity() {
+	a, b := &hashNode{1}, &hashNode{1}
+	suite.Equal(2, StreamOf([]*hashNode{a, b, a}).Uniq().Size())
+	suite.True(StreamOf([]*hashNode{a}).ToSet().Contains(a))
+	suite.False(StreamOf([]*hashNode{a}).ToSet().Contains(b))
+	suite.Equal(2, StreamOf([]interface{}{a, b, a}).Uniq().Size())
+	var set map[*hashNode]bool
+	suite.NoError(StreamOf([]*hashNode{a, b}).ToSet().To(&set))
+	suite.Len(set, 2)
+}
+
+func (suite *TestFPTestSuite) TestHashNegativeZero() {
+	negZero := math.Copysign(0, -1)
+	suite.Equal(hashOf(reflect.ValueOf(0.0)), hashOf(reflect.ValueOf(negZero)))
+	suite.Equal(1, StreamOf([][]float64{{0}, {negZero}}).Uniq().Size())
+	suite.Equal(1, StreamOf([]interface{}{[]float64{negZero}, []float64{0}}).Uniq().Size())
+}
+
+func (suite *TestFPTestSuite) TestUnhashable() {
+	var slices [][]int
+	StreamOf([][]int{{1, 2}, {1}, {1, 2}, nil, {}}).Uniq().ToSlice(&slices)
+	suite.Equal([][]int{{1, 2}, {1}, nil, {}}, slices)
+
+	points := []hashPoint{{1, 2, []string{"a"}}, {1, 2, []string{"a"}}, {1, 2, nil}}
+	suite.Equal(2, StreamOf(points).Uniq().Size())
+	suite.Equal(1, StreamOf(points).UniqBy(func(p hashPoint) []int { return []int{p.X, p.Y} }).Size())
+
+	var mixed []interface{}
+	StreamOf([]interface{}{[]int{1}, 1, []int{1}, map[string]int{"a": 1}, map[string]int{"a": 1}, 1}).Uniq().ToSlice(&mixed)
+	suite.Equal([]interface{}{[]int{1}, 1, map[string]int{"a": 1}}, mixed)
+
+	set := StreamOf(points).ToSet()
+	suite.True(set.Contains(hashPoint{1, 2, []string{"a"}}))
+	suite.False(set.Contains(hashPoint{1, 2, []string{"b"}}))
+	suite.Equal(2, set.Size())
+	var mp map[int]bool
+	suite.Error(set.To(&mp))
+
+	groups := StreamOf([]string{"a", "bb", "c"}).GroupBy(func(s string) []int { return []int{len(s)} })
+	suite.Equal(2, groups.Size())
+	var keys [][]int
+	groups.Keys().ToSlice(&keys)
+	suite.Equal([][]int{{1}, {2}}, keys)
+	var vals [][]string
+	StreamOf([]string{"a", "bb", "c"}).GroupBy(func(s string) []int { return []int{len(s)} }).Values().ToSlice(&vals)
+	suite.Equal([][]string{{"a", "c"}, {"bb"}}, vals)
+
+	suite.Equal(1, StreamOf(points).Sub(StreamOf(points[:1])).Size())
+	suite.Equal(2, StreamOf(points).Interact(StreamOf(points[:1])).Size())
+
+	byLen := []caseInsensitive{{"a"}, {"a"}, {"bb"}}
+	suite.Equal(2, StreamOf(byLen).Uniq().Size())
+
+	var iset map[interface{}]bool
+	suite.NoError(StreamOf([]interface{}{1, "a", 1}).ToSet().To(&iset))
+	suite.Equal(map[interface{}]bool{1: true, "a": true}, iset)
+
+	/* hashable dynamic values behind interface keep go map semantics */
+	type P struct{ X int }
+	a, b := &P{1}, &P{1}
+	suite.Equal(2, StreamOf([]interface{}{a, b, a}).Uniq().Size())
+	suite.Equal(2, StreamOf([]interface{}{a, b}).ToSet().Size())
+	suite.Equal(3, StreamOf([]interface{}{a, b, []int{1}, []int{1}}).ToSet().Size())
+	suite.Equal(2, StreamOf([]interface{}{a, b}).GroupBy(func(v interface{}) interface{} { return v }).Size())
+}
+
+func (suite *TestFPTestSuite) TestFastPath() {
+	suite.Equal([]int{2, 4}, StreamOf([]int{1, 2}).Map(func(i int) int { return i * 2 }).Ints())
+	suite.Equal([]string{"1"}, StreamOf([]int{1}).Map(strconv.Itoa).Strings())
+	suite.Equal([]string{"A"}, StreamOf([]string{"a"}).Map(strings.ToUpper).Strings())
+	suite.Equal([]int{1}, StreamOf([]string{"a"}).Map(func(s string) int { return len(s) }).Ints())
+	suite.Equal([]int64{2}, StreamOf([]int64{1}).Map(func(i int64) int64 { return i + 1 }).Int64s())
+	var floats []float64
+	StreamOf([]float64{1.5}).Map(math.Floor).ToSlice(&floats)
+	suite.Equal([]float64{1}, floats)
+
+	var any []interface{}
+	StreamOf([]interface{}{1, "a"}).Map(func(v interface{}) interface{} {
+		if _, ok := v.(int); ok {
+			return nil
+		}
+		return v
+	}).ToSlice(&any)
+	suite.Equal([]interface{}{nil, "a"}, any)
+
+	suite.Equal([]int{2}, StreamOf([]int{1, 2}).Filter(func(i int) bool { return i > 1 }).Ints())
+	suite.Equal([]int{1}, StreamOf([]int{1, 2}).Reject(func(i int) bool { return i > 1 }).Ints())
+	suite.Equal([]string{"a"}, StreamOf([]string{"a", "bb"}).TakeWhile(func(s string) bool { return len(s) == 1 }).Strings())
+	suite.Equal([]int64{2}, StreamOf([]int64{1, 2}).SkipWhile(func(i int64) bool { return i < 2 }).Int64s())
+	suite.Equal(1, StreamOf([]interface{}{1, nil}).Filter(func(v interface{}) bool { return v == nil }).Size())
+	suite.Equal(1, StreamOf([]float64{1, 2}).Filter(func(f float64) bool { return f > 1 }).Size())
+	suite.Equal(1, StreamOf([]int{1, 2}).Filter(func(v interface{}) bool { return v.(int) > 1 }).Size())
+}
+
+func (suite *TestFPTestSuite) TestStageFusion() {
+	var seen []int
+	s := StreamOf([]int{1, 2, 3, 4, 5, 6}).
+		Map(func(i int) int { return i * 10 }).
+		Filter(func(i int) bool { return i != 20 }).
+		Foreach(func(i int, idx int) { seen = append(seen, idx) }).
+		Reject(func(i int) bool { return i == 40 }).
+		TakeWhile(func(i int) bool { return i < 60 })
+	suite.Len(s.(*stream).fused.stages, 5)
+	suite.Equal([]int{10, 30, 50}, s.Ints())
+	suite.Equal([]int{0, 1, 2, 3, 4}, seen)
+
+	/* terminal ops that peek elements break fusion but keep them */
+	base := StreamOf([]int{1, 2, 3}).Map(func(i int) int { return i + 1 })
+	suite.Equal(2, base.First().Int())
+	suite.Nil(base.(*stream).fused)
+	suite.Equal([]int{4, 6, 8}, base.Map(func(i int) int { return i * 2 }).Ints())
+	base = StreamOf([]int{1, 2, 3}).Filter(func(i int) bool { return i > 1 })
+	suite.True(base.Contains(2))
+	suite.Equal([]int{2, 3}, base.Map(func(i int) int { return i }).Ints())
+	base = StreamOf([]int{1, 2, 3}).Filter(func(i int) bool { return i > 1 })
+	suite.False(base.IsEmpty())
+	suite.Equal([]int{3}, base.Filter(func(i int) bool { return i > 2 }).Ints())
+
+	/* results of fast path map reuse storage inside the loop only */
+	big := func(i int) int { return i * 1000 }
+	suite.Equal([]int{1000, 2000, 3000}, StreamOf([]int{3, 1, 2}).Map(big).Filter(func(i int) bool { return i > 0 }).Sort().Ints())
+	cached := StreamOf([]int{1, 2}).Map(big).Map(strconv.Itoa).Cache()
+	suite.Equal([]string{"1000", "2000"}, cached.Strings())
+	suite.Equal([]string{"2000", "1000"}, cached.Reverse().Strings())
+	base = StreamOf([]int{1, 2, 3}).Map(big)
+	suite.True(base.Contains(2000))
+	suite.Equal([]int{1000, 2000, 3000}, base.Ints())
+
+	/* errors of fused map */
+	err := StreamOf([]int{1, 2, 3, 4}).
+		Filter(func(i int) bool { return i > 1 }).
+		Map(func(i int) (int, error) {
+			if i%2 == 0 {
+				return 0, fmt.Errorf("bad %d", i)
+			}
+			return i, nil
+		}, OnError(CollectErrors)).
+		Map(func(i int) int { return i }).
+		Error()
+	suite.Equal(2, len(err.(*MultiError).Errors))
+	var count int
+	err = StreamOf([]int{1, 2, 3}).
+		Map(func(i int) (int, error) {
+			if i == 2 {
+				return 0, errors.New("stop")
+			}
+			return i, nil
+		}).
+		Foreach(func(int) { count++ }).
+		Error()
+	suite.EqualError(err, "stop")
+	suite.Equal(1, count)
+
+	/* safe mode keeps failed element of each op */
+	err = StreamOf([]int{1, 2}).SafeMode().
+		Map(func(i int) int { return i * 10 }).
+		Filter(func(i int) bool {
+			if i == 20 {
+				panic("boom")
+			}
+			return true
+		}).Error()
+	suite.Equal(20, err.(*PanicError).Element)
+}
+
+func (suite *TestFPTestSuite) TestSizedSource() {
+	arr := []int{0, 1, 2, 3, 4, 5}
+	suite.Equal(6, StreamOf(arr).Size())
+	suite.Equal(5, Times(5).Size())
+	suite.Equal(3, RangeStream(1, 3).Size())
+	suite.Equal(2, StreamOf([2]string{"a", "b"}).Size())
+
+	/* views of source */
+	s := StreamOf(arr).Skip(1).Take(4).Reverse()
+	suite.NotNil(s.(*stream).src)
+	suite.Equal(4, s.Size())
+	suite.Equal([]int{4, 3, 2, 1}, StreamOf(arr).Skip(1).Take(4).Reverse().Ints())
+	suite.Equal([]int{3, 2}, StreamOf(arr).Reverse().Skip(2).Take(2).Ints())
+	suite.Equal([]int{4, 5}, Times(6).Skip(4).Ints())
+	suite.Equal([]int{2, 1}, RangeStream(1, 5).Take(2).Reverse().Ints())
+	suite.Equal([]string{"b", "a"}, StreamOf([2]string{"a", "b"}).Reverse().Strings())
+	suite.Empty(StreamOf(arr).Skip(10).Ints())
+	suite.Empty(StreamOf(arr).Take(-1).Ints())
+	suite.Equal(arr, StreamOf(arr).Skip(-1).Take(10).Ints())
+
+	/* consumed elements are not counted */
+	s = StreamOf(arr)
+	s.ToSource().Next()
+	suite.Equal(5, s.Size())
+	s = StreamOf(arr)
+	suite.Equal(arr, s.Ints())
+	suite.Equal(6, s.Size())
+
+	/* filtering stage disables shortcuts */
+	s = StreamOf(arr).Filter(func(i int) bool { return i > 2 })
+	suite.Nil(s.(*stream).src)
+	suite.Equal(3, s.Size())
+	suite.Equal([]int{5, 4}, StreamOf(arr).Filter(func(i int) bool { return i > 3 }).Reverse().Ints())
+
+	/* partitions of slice share memory with it */
+	src := []int{0, 1, 2, 3, 4}
+	var parts [][]int
+	suite.NoError(StreamOf(src).Partition(2).ToSlice(&parts))
+	suite.Equal([][]int{{0, 1}, {2, 3}, {4}}, parts)
+	parts[0][0] = 10
+	suite.Equal(10, src[0])
+	parts[0] = append(parts[0], 20)
+	suite.Equal(2, src[2])
+	suite.Equal([][]string{{"a", "b"}, {"c"}}, StreamOf([3]string{"a", "b", "c"}).Partition(2).StringsList())
+	suite.Equal([]int{4, 3, 2, 1, 0}, StreamOf([]int{3, 1, 4, 0, 2}).Sort().Reverse().Ints())
+}
+
+func (suite *TestFPTestSuite) TestSizedSourceViewsConsumeParent() {
+	arr := []int{1, 2, 3}
+	s := StreamOf(arr)
+	suite.Equal([]int{1}, s.Take(1).Ints())
+	suite.Equal([]int{2, 3}, s.Ints())
+
+	s = Times(6)
+	suite.Equal([]int{2}, s.Skip(2).Take(1).Ints())
+	suite.Equal([]int{3, 4, 5}, s.Ints())
+
+	/* view is consumed as it is read */
+	s = StreamOf([]int{0, 1, 2, 3, 4})
+	it := s.Skip(1).Iterator()
+	suite.True(it.Next())
+	suite.Equal(1, it.Value())
+	suite.Equal([]int{2, 3, 4}, s.Ints())
+
+	s = StreamOf(arr)
+	suite.Equal(2, s.Take(2).Size())
+	suite.Equal([]int{3}, s.Ints())
+
+	s = StreamOf(arr)
+	suite.Equal([]int{3, 2, 1}, s.Reverse().Ints())
+	suite.Equal(arr, s.Ints())
+	s = StreamOf([]int{0, 1, 2, 3})
+	suite.Equal([]int{2, 1}, s.Skip(1).Take(2).Reverse().Ints())
+	suite.Equal([]int{3}, s.Ints())
+}
+
+func (suite *TestFPTestSuite) TestSizedSourceSizeKeepsElements() {
+	arr := []int{1, 2, 3}
+	s := StreamOf(arr)
+	suite.Equal(3, s.Size())
+	suite.Equal(3, s.Size())
+	suite.Equal(arr, s.Ints())
+
+	s = Times(3)
+	suite.Equal(3, s.Size())
+	suite.Equal([]int{0, 1, 2}, s.Ints())
+
+	s = StreamOf([]int{3, 1, 2}).Sort()
+	suite.Equal(3, s.Size())
+	suite.Equal(3, s.Size())
+	suite.Equal(arr, s.Ints())
+
+	/* view keeps its elements, source it is cut from is consumed once */
+	s = StreamOf([]int{0, 1, 2, 3})
+	v := s.Skip(1).Take(2)
+	suite.Equal(2, v.Size())
+	suite.Equal([]int{1, 2}, v.Ints())
+	suite.Equal([]int{3}, s.Ints())
+}
+
+func (suite *TestFPTestSuite) TestSortShortcuts() {
+	var compared int
+	less := func(a, b int) bool {
+		compared++
+		return a < b
+	}
+	s := StreamOf([]int{3, 1, 2}).SortBy(less)
+	suite.NotNil(s.(*stream).src)
+	suite.Equal(3, s.Size())
+	suite.Zero(compared)
+
+	suite.Equal([]int{3, 2}, StreamOf([]int{3, 1, 2}).Sort().Reverse().Take(2).Ints())
+	suite.Equal([]int{2, 3}, StreamOf([]int{3, 1, 2}).Sort().Skip(1).Ints())
+	suite.Equal(2, StreamOf([]int{3, 1, 2}).Filter(func(i int) bool { return i > 1 }).Sort().Size())
+}
+
+func (suite *TestFPTestSuite) TestMerge() {
+	out := Merge(StreamOf([]int{1, 2}), Times(3), newNilStream(), StreamOf([]int{5}).Map(func(i int) int { return i * 2 })).Sort().Ints()
+	suite.Equal([]int{0, 1, 1, 2, 2, 10}, out)
+	suite.Equal(0, Merge().Size())
+	suite.Panics(func() { Merge(Times(1), StreamOf([]string{"a"})) })
+
+	err := Merge(Times(3), StreamOf([]int{1, 2}).Map(func(i int) (int, error) {
+		return i, errors.New("bad")
+	})).Error()
+	suite.EqualError(err, "bad")
+
+	/* closing merged stream closes infinite inputs */
+	var wg sync.WaitGroup
+	wg.Add(2)
+	suite.Equal(5, Merge(NaturalNumbers().Finally(wg.Done), NaturalNumbers().Finally(wg.Done)).Take(5).Size())
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.Fail("inputs of merged stream are not closed")
+	}
+	wg.Add(1)
+	Merge(Times(3).Finally(wg.Done)).Close()
+	wg.Wait()
+}
+
+func (suite *TestFPTestSuite) TestMergeIdleChannelInput() {
+	idle := make(chan int)
+	closed := make(chan struct{})
+	var completed bool
+	s := StreamOf(idle).OnComplete(func() { completed = true }).Finally(func() { close(closed) })
+	suite.Equal([]int{1, 2}, Merge(s, StreamOf([]int{1, 2})).Take(2).Sort().Ints())
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		suite.Fail("idle channel input of merged stream is not closed")
+	}
+	suite.False(completed)
+}
+
+func (suite *TestFPTestSuite) TestMergeChannels() {
+	ch1, ch2 := make(chan int), make(chan int)
+	var nilCh chan int
+	go func() {
+		ch1 <- 1
+		ch2 <- 2
+		ch1 <- 3
+		close(ch1)
+		ch2 <- 4
+		close(ch2)
+	}()
+	suite.Equal([]int{1, 2, 3, 4}, MergeChannels(ch1, nilCh, (<-chan int)(ch2)).Ints())
+	suite.Equal(0, MergeChannels().Size())
+	suite.Panics(func() { MergeChannels(ch1, make(chan string)) })
+	suite.Panics(func() { MergeChannels(make(chan<- int)) })
+	suite.Panics(func() { MergeChannels([]int{1}) })
+}
+
+func (suite *TestFPTestSuite) TestShard() {
+	shards := Times(10).Shard(3, nil)
+	suite.Len(shards, 3)
+	outs := make([][]int, 3)
+	var wg sync.WaitGroup
+	for i := range shards {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outs[i] = shards[i].Ints()
+		}(i)
+	}
+	wg.Wait()
+	suite.Equal([][]int{{0, 3, 6, 9}, {1, 4, 7}, {2, 5, 8}}, outs)
+
+	/* same key goes to the same shard */
+	var mu sync.Mutex
+	var workers int64
+	owners := map[int]map[int64]bool{}
+	err := Times(100).Distribute(4, func(i int) int { return i % 10 }, func(s Stream) {
+		id := atomic.AddInt64(&workers, 1)
+		s.Foreach(func(i int) {
+			mu.Lock()
+			defer mu.Unlock()
+			if owners[i%10] == nil {
+				owners[i%10] = map[int64]bool{}
+			}
+			owners[i%10][id] = true
+		}).Run()
+	})
+	suite.NoError(err)
+	suite.Len(owners, 10)
+	for _, ids := range owners {
+		suite.Len(ids, 1)
+	}
+
+	/* a shard abandoned early does not block the others */
+	var count int64
+	err = Times(1000).Distribute(2, nil, func(s Stream) {
+		atomic.AddInt64(&count, int64(s.Take(1).Size()))
+	})
+	suite.NoError(err)
+	suite.Equal(int64(2), count)
+	suite.Panics(func() { Times(1).Shard(0, nil) })
+	suite.Len(newNilStream().Shard(2, nil), 2)
+}
+
+func (suite *TestFPTestSuite) TestShardUnreadBlocksOthers() {
+	shards := Times(1000).Shard(2, nil)
+	it := shards[0].Iterator()
+	got := make(chan int)
+	go func() {
+		defer close(got)
+		for it.Next() {
+			got <- it.Value().(int)
+		}
+	}()
+	/* shard 1 is never read, dispatcher blocks once its buffer is full */
+	var out []int
+	for len(out) <= shardBuffer {
+		out = append(out, <-got)
+	}
+	select {
+	case v := <-got:
+		suite.Failf("shard should block", "got %v", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+	suite.Len(out, shardBuffer+1)
+	suite.Equal(2*shardBuffer, out[shardBuffer])
+
+	/* closing the unread shard releases the others */
+	shards[1].Close()
+	for v := range got {
+		out = append(out, v)
+	}
+	suite.Len(out, 500)
+}
+
+func (suite *TestFPTestSuite) TestDistributeErrors() {
+	bad := errors.New("bad")
+	err := StreamOf([]int{1, 2, 3, 4}).Map(func(i int) (int, error) {
+		if i == 3 {
+			return 0, bad
+		}
+		return i, nil
+	}).Distribute(2, nil, func(s Stream) { s.Run() })
+	suite.Equal(bad, err)
+
+	err = Times(4).Distribute(2, nil, func(s Stream) {
+		s.Map(func(i int) (int, error) { return 0, fmt.Errorf("worker %d", i) }).Run()
+	})
+	suite.Len(err.(*MultiError).Errors, 2)
+
+	/* panics on dispatching goroutine fail the stream */
+	err = Times(4).Distribute(2, func(i int) int {
+		if i == 2 {
+			panic("bad key")
+		}
+		return i
+	}, func(s Stream) { s.Run() })
+	suite.Equal("bad key", err.(*PanicError).Value)
+	suite.Equal(2, err.(*PanicError).Element)
+	err = Times(4).SafeMode().Map(func(i int) int { return 10 / (i - 1) }).Distribute(2, nil, func(s Stream) { s.Run() })
+	suite.IsType(&PanicError{}, err)
+
+	err = Times(4).Distribute(2, nil, func(s Stream) { panic("boom") })
+	suite.Len(err.(*MultiError).Errors, 2)
+	suite.Equal("boom", err.(*MultiError).Errors[0].(*PanicError).Value)
+}
+
 func (suite *TestFPTestSuite) TestResult() {
 	type S interface {
 		String() string
@@ -686,6 +1387,21 @@ func (suite *TestFPTestSuite) TestFlattenInnerEmptyStream() {
 	suite.Len(fullnames, 0)
 }
 
+func (suite *TestFPTestSuite) TestFlattenInnerChannelStreamIsLazy() {
+	ch := make(chan int)
+	defer close(ch)
+	/* constructing Flatten must not pull the idle inner stream */
+	s := StreamOf([]Stream{StreamOf(ch)}).Flatten()
+	suite.Equal(reflect.TypeOf(0), s.ToSource().ElemType())
+
+	var pulled int
+	inner := StreamOf([]int{1, 2}).Foreach(func(int) { pulled++ })
+	s = StreamOf([]Stream{StreamOf([]int{}), inner}).Flatten()
+	suite.Zero(pulled)
+	suite.Equal([]int{1, 2}, s.Ints())
+	suite.Equal(2, pulled)
+}
+
 func (suite *TestFPTestSuite) TestFlattenInnerStreamButOuterIsEmpty() {
 	databases := []string{}
 	tables := []string{"table1", "table2"}
@@ -1591,6 +2307,259 @@ func (suite *TestFPTestSuite) TestZipnFuncCheck() {
 	})
 }
 
+func (suite *TestFPTestSuite) TestValidateFunc() {
+	suite.PanicsWithValue("fp: Filter expects func(int) bool, got func(int) string", func() {
+		StreamOf([]int{1}).Filter(func(int) string { return "" })
+	})
+	suite.PanicsWithValue("fp: Foreach expects func(int) or func(int, int), got func(string)", func() {
+		StreamOf([]int{1}).Foreach(func(string) {})
+	})
+	suite.PanicsWithValue("fp: ZipN expects func(int, string) any_type, got func(int) string", func() {
+		StreamOf([]int{1}).ZipN(func(int) string { return "" }, StreamOf([]string{"a"}))
+	})
+	suite.PanicsWithValue("fp: SortBy expects func(int, int) bool, got <nil>", func() {
+		StreamOf([]int{1}).SortBy(nil)
+	})
+	suite.PanicsWithValue("fp: KVStream.Map expects func(string, int) (any_type, any_type) or func(string, int) (any_type, any_type, error), got func(string, int) string", func() {
+		KVStreamOf(map[string]int{}).Map(func(string, int) string { return "" })
+	})
+	suite.PanicsWithValue("fp: Monad.Map expects func(string) any_type or func(string) (any_type, bool) or func(string) (any_type, error), got func(int) int", func() {
+		M("a").Map(func(i int) int { return i })
+	})
+	suite.PanicsWithValue("fp: When expects func(int) bool, got func(string) bool", func() {
+		When(func(string) bool { return true }).Then(func(i int) int { return i }).Else(func(i int) int { return i })
+	})
+	suite.NotPanics(func() {
+		StreamOf([]int{1}).Filter(func(interface{}) bool { return true }).Run()
+	})
+}
+
+func (suite *TestFPTestSuite) TestPipeline() {
+	p := Pipe().Filter(func(i int) bool {
+		return i%2 == 0
+	}).Map(func(i int) string {
+		return strconv.Itoa(i)
+	}).Take(2)
+	suite.Equal([]string{"0", "2"}, p.Apply(Times(10)).Strings())
+	suite.Equal([]string{"4", "6"}, p.Apply(RangeStream(3, 10)).Strings())
+	suite.Equal([]string{"8"}, p.Apply(StreamOf([]int{1, 8})).Strings())
+
+	q := Pipe().Map(strings.ToUpper)
+	suite.Equal([]string{"0!", "2!"}, p.Then(q).Map(func(s string) string { return s + "!" }).Apply(Times(3)).Strings())
+
+	batch := Pipe().Take(3).Partition(2).Flatten().Map(func(i int) int { return i * 10 })
+	suite.Equal([]int{0, 10, 20}, batch.Apply(Times(5)).Ints())
+}
+
+func (suite *TestFPTestSuite) TestPipelineValidation() {
+	suite.PanicsWithValue("fp: Pipeline.Filter expects func(string) bool, got func(int) bool", func() {
+		Pipe().Map(strconv.Itoa).Filter(func(int) bool { return true })
+	})
+	suite.PanicsWithValue("fp: Pipeline expects stream of int, got string", func() {
+		Pipe().Take(1).Map(strconv.Itoa).Apply(StreamOf([]string{"a"}))
+	})
+	suite.PanicsWithValue("fp: Pipeline.Then expects pipeline of string, got int", func() {
+		Pipe().Map(strconv.Itoa).Then(Pipe().Filter(func(int) bool { return true }))
+	})
+}
+
+func (suite *TestFPTestSuite) TestCache() {
+	var pulled int
+	s := Times(5).Foreach(func(int) { pulled++ }).Cache()
+	suite.Equal([]int{0, 1}, s.Take(2).Ints())
+	suite.Equal(2, pulled)
+	suite.Equal([]int{0, 2, 4}, s.Filter(func(i int) bool { return i%2 == 0 }).Ints())
+	suite.Equal(5, pulled)
+	suite.Equal([]int{0, 1, 2, 3, 4}, s.Ints())
+	suite.Equal([]int{0, 1, 2, 3, 4}, s.Ints())
+	suite.Equal([]int{1, 2, 3, 4, 5}, s.Map(func(i int) int { return i + 1 }).Ints())
+	suite.Equal(5, pulled)
+
+	src := s.ToSource()
+	v, ok := src.Next()
+	suite.True(ok)
+	suite.Equal(0, v.Interface())
+	v, _ = s.ToSource().Next()
+	suite.Equal(0, v.Interface())
+}
+
+func (suite *TestFPTestSuite) TestCacheInfinite() {
+	s := NaturalNumbers().Cache()
+	suite.Equal([]uint64{0, 1, 2}, s.Take(3).Uint64s())
+	suite.Equal([]uint64{0, 1, 2, 3}, s.Take(4).Uint64s())
+}
+
+func (suite *TestFPTestSuite) TestCacheTerminalsReplay() {
+	sum := func(a, b int) int { return a + b }
+	s := Times(5).Cache()
+	suite.Equal(10, s.Reduce(0, sum).Int())
+	suite.Equal(10, s.Reduce(0, sum).Int())
+	suite.Equal(10, s.Reduce0(sum).Int())
+	suite.Equal(10, s.Reduce0(sum).Int())
+	suite.Equal(0, s.First().Int())
+	suite.Equal(0, s.First().Int())
+	suite.True(s.Contains(4))
+	suite.True(s.Contains(4))
+	suite.True(s.ContainsBy(func(i int) bool { return i == 4 }))
+	suite.True(s.ContainsBy(func(i int) bool { return i == 4 }))
+	suite.False(s.IsEmpty())
+	suite.False(s.IsEmpty())
+	s.Run()
+	s.Run()
+	suite.Equal(5, s.Size())
+	suite.Equal(5, s.Size())
+	suite.Equal(10, s.Reduce(0, sum).Int())
+	suite.Equal([]int{0, 1, 2, 3, 4}, s.Ints())
+
+	s = Times(5).Cache()
+	s.Run()
+	suite.Equal([]int{0, 1, 2, 3, 4}, s.Ints())
+	suite.Equal(10, s.Reduce0(sum).Int())
+}
+
+func (suite *TestFPTestSuite) TestCacheLimit() {
+	s := Times(5).CacheLimit(2)
+	suite.Equal([]int{0, 1, 2, 3, 4}, s.Ints())
+	var out []int
+	err := s.Map(func(i int) int { return i }).ToSlice(&out)
+	suite.True(errors.Is(err, ErrCacheEvicted))
+
+	s = Times(5).CacheLimit(2)
+	suite.Equal([]int{0, 1}, s.Take(2).Ints())
+	suite.Equal([]int{0, 1}, s.Take(2).Ints())
+
+	/* eviction only fails the cursor falling behind */
+	s = Times(5).CacheLimit(2)
+	ahead := s.Map(func(i int) int { return i }).Iterator()
+	behind := s.Map(func(i int) int { return i }).Iterator()
+	for i := 0; i < 3; i++ {
+		suite.True(ahead.Next())
+	}
+	suite.False(behind.Next())
+	suite.True(errors.Is(behind.Err(), ErrCacheEvicted))
+	suite.True(ahead.Next())
+	suite.Equal(3, ahead.Value())
+	suite.True(ahead.Next())
+	suite.False(ahead.Next())
+	suite.NoError(ahead.Err())
+}
+
+func (suite *TestFPTestSuite) TestCacheHooks() {
+	var a, b int
+	s := Times(5).Cache()
+	suite.Equal([]int{0, 1}, s.Take(2).OnComplete(func() { a++ }).Ints())
+	suite.Equal(5, s.Map(func(i int) int { return i }).OnComplete(func() { b++ }).Size())
+	suite.Equal(1, a)
+	suite.Equal(1, b)
+}
+
+func (suite *TestFPTestSuite) TestCacheError() {
+	var out []int
+	s := StreamOf([]string{"1", "a"}).Map(strconv.Atoi).Cache()
+	suite.Error(s.ToSlice(&out))
+	suite.Equal([]int{1}, out)
+	suite.Error(s.Filter(func(int) bool { return true }).Error())
+}
+
+func (suite *TestFPTestSuite) TestIterator() {
+	var completed bool
+	it := StreamOf([]int{1, 2, 3}).Map(func(i int) int { return i * 2 }).OnComplete(func() { completed = true }).Iterator()
+	var out []int
+	for it.Next() {
+		var i int
+		suite.NoError(it.Scan(&i))
+		suite.Equal(i, it.Value())
+		out = append(out, i)
+	}
+	suite.Equal([]int{2, 4, 6}, out)
+	suite.NoError(it.Err())
+	suite.True(completed)
+	suite.False(it.Next())
+	suite.Nil(it.Value())
+
+	var str string
+	it = StreamOf([]int{1}).Iterator()
+	suite.Error(it.Scan(&str))
+	suite.True(it.Next())
+	suite.Error(it.Scan(&str))
+	suite.Error(it.Scan(str))
+
+	it = StreamOf([]string{"1", "a", "3"}).Map(strconv.Atoi).Iterator()
+	suite.True(it.Next())
+	suite.NoError(it.Err())
+	suite.False(it.Next())
+	suite.Error(it.Err())
+
+	var closed bool
+	it = Times(100).Finally(func() { closed = true }).Iterator()
+	suite.True(it.Next())
+	suite.NoError(it.Close())
+	suite.True(closed)
+	suite.False(it.Next())
+
+	suite.False(newNilStream().Iterator().Next())
+}
+
+type selectorAddress struct {
+	City string
+}
+
+type selectorUser struct {
+	Name    string
+	Age     int
+	Address *selectorAddress
+	Tags    []string
+	Labels  map[string]string
+	Extra   map[string]interface{}
+	secret  string
+}
+
+func (suite *TestFPTestSuite) TestPluck() {
+	users := []selectorUser{
+		{Name: "a", Age: 30, Address: &selectorAddress{City: "x"}, Tags: []string{"t1"}, Labels: map[string]string{"env": "prod"}},
+		{Name: "b", Age: 20, Extra: map[string]interface{}{"score": 3}},
+	}
+	suite.Equal([]string{"a", "b"}, StreamOf(users).Map(Pluck("Name")).Strings())
+	suite.Equal([]string{"x", ""}, StreamOf(users).Map(Pluck("Address.City")).Strings())
+	suite.Equal([]string{"t1", ""}, StreamOf(users).Map(Pluck("Tags.0")).Strings())
+	suite.Equal([]string{"prod", ""}, StreamOf(users).Map(Pluck("Labels.env")).Strings())
+	var scores []interface{}
+	StreamOf(users).Map(Pluck("Extra.score")).ToSlice(&scores)
+	suite.Equal([]interface{}{nil, 3}, scores)
+	suite.Equal([]string{"x"}, StreamOf([]*selectorUser{{Address: &selectorAddress{City: "x"}}}).Map(Pluck("Address.City")).Strings())
+
+	nested := []interface{}{map[string]interface{}{"a": map[string]int{"b": 1}}, 1}
+	var out []interface{}
+	StreamOf(nested).Map(Pluck("a.b")).ToSlice(&out)
+	suite.Equal([]interface{}{1, nil}, out)
+
+	suite.Panics(func() { StreamOf(users).Map(Pluck("Unknown")) })
+	suite.Panics(func() { StreamOf(users).Map(Pluck("secret")) })
+	suite.Panics(func() { StreamOf(users).Map(Pluck("Tags.x")) })
+	suite.Panics(func() { StreamOf(users).Map(Pluck("Name.x")) })
+}
+
+func (suite *TestFPTestSuite) TestSelect() {
+	users := []selectorUser{{Name: "a", Age: 30, Address: &selectorAddress{City: "x"}}}
+	var out []map[string]interface{}
+	StreamOf(users).Map(Select("Name", "Address.City")).ToSlice(&out)
+	suite.Equal([]map[string]interface{}{{"Name": "a", "Address.City": "x"}}, out)
+	suite.Panics(func() { Select() })
+}
+
+func (suite *TestFPTestSuite) TestSelectorAsKey() {
+	users := []selectorUser{{Name: "a", Age: 30}, {Name: "b", Age: 20}, {Name: "c", Age: 30}}
+	suite.Equal([]string{"b", "a", "c"}, StreamOf(users).SortBy(Pluck("Age")).Map(Pluck("Name")).Strings())
+	suite.Equal([]string{"a", "b"}, StreamOf(users).UniqBy(Pluck("Age")).Map(Pluck("Name")).Strings())
+	suite.Equal(2, StreamOf(users).GroupBy(Pluck("Age")).Size())
+	suite.True(StreamOf(users).ToSetBy(Pluck("Name")).Contains("c"))
+
+	others := []*selectorUser{{Name: "c"}, {Name: "d"}}
+	suite.Equal([]string{"c"}, StreamOf(users).InteractBy(StreamOf(others), Pluck("Name")).Map(Pluck("Name")).Strings())
+	suite.Equal([]string{"a", "b"}, StreamOf(users).SubBy(StreamOf(others), Pluck("Name")).Map(Pluck("Name")).Strings())
+	suite.Equal([]string{"b", "a", "c"}, Pipe().SortBy(func(a, b selectorUser) bool { return a.Age < b.Age }).Map(Pluck("Name")).Apply(StreamOf(users)).Strings())
+}
+
 func (suite *TestFPTestSuite) TestStreamMustHaveIterator() {
 	s := newStream(nil, reflect.TypeOf(1), nil)
 	suite.NotNil(s.iter)
@@ -1668,6 +2637,109 @@ func (suite *TestFPTestSuite) TestCompare() {
 	suite.Equal(1, StreamOf([]TupleStringInt{}).(*stream).compare(reflect.ValueOf(TupleStringInt{E2: 3}), reflect.ValueOf(TupleStringInt{E2: 2})))
 }
 
+type orderVersion struct{ major, minor int }
+
+func (v orderVersion) Compare(o orderVersion) int {
+	if v.major != o.major {
+		return v.major - o.major
+	}
+	return v.minor - o.minor
+}
+
+type orderPriority string
+
+func (p orderPriority) Less(o orderPriority) bool {
+	rank := map[orderPriority]int{"high": 0, "mid": 1, "low": 2}
+	return rank[p] < rank[o]
+}
+
+func (suite *TestFPTestSuite) TestCompareExtended() {
+	suite.Equal(-1, StreamOf([]float64{}).(*stream).compare(reflect.ValueOf(1.5), reflect.ValueOf(2.0)))
+	now := time.Now()
+	suite.Equal(-1, StreamOf([]time.Time{}).(*stream).compare(reflect.ValueOf(now), reflect.ValueOf(now.Add(time.Second))))
+	suite.Equal(0, StreamOf([]time.Time{}).(*stream).compare(reflect.ValueOf(now), reflect.ValueOf(now)))
+
+	versions := []orderVersion{{1, 10}, {1, 2}, {0, 9}}
+	var sorted []orderVersion
+	StreamOf(versions).Sort().ToSlice(&sorted)
+	suite.Equal([]orderVersion{{0, 9}, {1, 2}, {1, 10}}, sorted)
+
+	var priorities []orderPriority
+	StreamOf([]orderPriority{"low", "high", "mid"}).Sort().ToSlice(&priorities)
+	suite.Equal([]orderPriority{"high", "mid", "low"}, priorities)
+}
+
+func (suite *TestFPTestSuite) TestNaturalOrdering() {
+	var floats []float64
+	StreamOf([]float64{10, math.NaN(), 9, -1.5}).Sort().ToSlice(&floats)
+	suite.True(math.IsNaN(floats[0]))
+	suite.Equal([]float64{-1.5, 9, 10}, floats[1:])
+	suite.True(StreamOf([]float64{1, math.NaN()}).Contains(math.NaN()))
+
+	now := time.Now()
+	times := []time.Time{now.Add(time.Hour), now, now.Add(-time.Hour)}
+	var sortedTimes []time.Time
+	StreamOf(times).Sort().ToSlice(&sortedTimes)
+	suite.Equal([]time.Time{times[2], times[1], times[0]}, sortedTimes)
+	suite.True(StreamOf(times).Contains(now.UTC()))
+
+	/* unexported fields are not equal just because they can't be interfaced */
+	type R struct {
+		ID int
+		m  map[string]int
+	}
+	suite.False(StreamOf([]R{{1, map[string]int{"a": 1}}}).Contains(R{1, map[string]int{"b": 2}}))
+	suite.True(StreamOf([]R{{1, map[string]int{"a": 1}}}).Contains(R{1, map[string]int{"a": 1}}))
+	suite.NotEqual(0, compareValue(reflect.TypeOf(R{}), reflect.ValueOf(R{1, map[string]int{"a": 1}}), reflect.ValueOf(R{1, map[string]int{"b": 2}})))
+
+	var blobs [][]byte
+	StreamOf([][]byte{[]byte("b"), []byte("ab"), []byte("a")}).Sort().ToSlice(&blobs)
+	suite.Equal([][]byte{[]byte("a"), []byte("ab"), []byte("b")}, blobs)
+
+	var arrays [][2]int
+	StreamOf([][2]int{{1, 10}, {1, 9}, {0, 100}}).Sort().ToSlice(&arrays)
+	suite.Equal([][2]int{{0, 100}, {1, 9}, {1, 10}}, arrays)
+
+	type point struct {
+		X, y int
+	}
+	var points []point
+	StreamOf([]point{{2, 1}, {1, 10}, {1, 9}}).Sort().ToSlice(&points)
+	suite.Equal([]point{{1, 9}, {1, 10}, {2, 1}}, points)
+
+	one, two := 1, 2
+	var ptrs []*int
+	StreamOf([]*int{&two, nil, &one}).Sort().ToSlice(&ptrs)
+	suite.Equal([]*int{nil, &one, &two}, ptrs)
+
+	var mixed []interface{}
+	StreamOf([]interface{}{10, "b", 9, "a"}).Sort().ToSlice(&mixed)
+	suite.Equal([]interface{}{9, 10, "a", "b"}, mixed)
+}
+
+func (suite *TestFPTestSuite) TestOrderBy() {
+	users := []selectorUser{{Name: "a", Age: 20}, {Name: "b", Age: 30}, {Name: "c", Age: 20}, {Name: "d", Age: 30}}
+	byAgeDescName := OrderByDesc(func(u selectorUser) int { return u.Age }).ThenBy(Pluck("Name"))
+	suite.Equal([]string{"b", "d", "a", "c"}, StreamOf(users).SortBy(byAgeDescName).Map(Pluck("Name")).Strings())
+	suite.Equal([]string{"c", "a", "d", "b"}, StreamOf(users).SortBy(OrderBy(Pluck("Age")).ThenByDesc(Pluck("Name"))).Map(Pluck("Name")).Strings())
+
+	less := byAgeDescName.Less().(func(a, b selectorUser) bool)
+	suite.True(less(users[1], users[0]))
+	suite.False(less(users[0], users[2]) && less(users[2], users[0]))
+	cmp := byAgeDescName.Compare().(func(a, b selectorUser) int)
+	suite.Equal(1, cmp(users[0], users[1]))
+	suite.Equal(0, cmp(users[0], users[0]))
+
+	events := []time.Time{time.Unix(2, 0), time.Unix(1, 0)}
+	var out []time.Time
+	StreamOf(events).SortBy(OrderBy(func(t time.Time) time.Time { return t })).ToSlice(&out)
+	suite.Equal(time.Unix(1, 0), out[0])
+
+	suite.Panics(func() { OrderBy(Pluck("Age")).Less() })
+	suite.Panics(func() { OrderBy(1) })
+	suite.Panics(func() { StreamOf(users).SortBy(OrderBy(func(i int) int { return i })) })
+}
+
 func (suite *TestFPTestSuite) TestToXXX() {
 	suite.Equal([]int64{1}, StreamOf([]int64{1}).Int64s())
 	suite.Equal([]int32{1}, StreamOf([]int32{1}).Int32s())
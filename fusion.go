@@ -0,0 +1,116 @@
+package fp
+
+import "reflect"
+
+/* stage fusion: consecutive element-wise ops (Map, Filter, Reject, Foreach, TakeWhile) run in one loop
+ * instead of wrapping iterator of previous op in another closure
+ */
+
+type stageResult int
+
+const (
+	emitElem stageResult = iota
+	skipElem
+	stopStream
+)
+
+/* stage is an element-wise op */
+type stage struct {
+	run func(reflect.Value) (reflect.Value, stageResult)
+	/* end is called once upstream of the stage is exhausted, optional */
+	end func()
+	/* passThrough stage emits its input as is */
+	passThrough bool
+	/* borrowed output reuses storage across elements, it's detached before leaving the loop */
+	borrowed bool
+}
+
+type fusion struct {
+	source iterator
+	stages []stage
+}
+
+/* fuse derive a stream running st after q, st joins the loop of q if q is fused */
+func (q *stream) fuse(ctx context, typ reflect.Type, st stage) Stream {
+	if ctx.Safe() {
+		/* recoverIter records input of each op, so every op keeps its own iterator */
+		return newStream(ctx, typ, q.upstream(ctx), func(next iterator) iterator {
+			return (&fusion{source: next, stages: []stage{st}}).iterator()
+		})
+	}
+	f := &fusion{source: q.upstream(ctx), stages: []stage{st}}
+	if q.fused != nil {
+		stages := make([]stage, 0, len(q.fused.stages)+1)
+		f = &fusion{source: q.fused.source, stages: append(append(stages, q.fused.stages...), st)}
+	}
+	s := newStream(ctx, typ, f.iterator())
+	if ctx.Err() == nil {
+		s.fused = f
+	}
+	return s
+}
+
+func (f *fusion) iterator() iterator {
+	var done bool
+	return func() (reflect.Value, bool) {
+	loop:
+		for !done {
+			val, ok := f.source()
+			if !ok {
+				done = true
+				f.end(0)
+				break
+			}
+			var borrowed bool
+			for i := range f.stages {
+				st := &f.stages[i]
+				out, res := st.run(val)
+				switch res {
+				case skipElem:
+					continue loop
+				case stopStream:
+					done = true
+					f.end(i + 1)
+					break loop
+				}
+				val = out
+				if st.borrowed {
+					borrowed = true
+				} else if !st.passThrough {
+					borrowed = false
+				}
+			}
+			if borrowed {
+				return detach(val), true
+			}
+			return val, true
+		}
+		return reflect.Value{}, false
+	}
+}
+
+/* end notify stages from i on that their upstream is exhausted */
+func (f *fusion) end(i int) {
+	for _, st := range f.stages[i:] {
+		if st.end != nil {
+			st.end()
+		}
+	}
+}
+
+/* detach copy v into its own storage, basic types are boxed like reflect.ValueOf does */
+func detach(v reflect.Value) reflect.Value {
+	switch v.Type() {
+	case intType:
+		return reflect.ValueOf(int(v.Int()))
+	case int64Type:
+		return reflect.ValueOf(v.Int())
+	case float64Type:
+		return reflect.ValueOf(v.Float())
+	case stringType:
+		return reflect.ValueOf(v.String())
+	}
+	out := reflect.New(v.Type()).Elem()
+	out.Set(v)
+	return out
+}
@@ -3,12 +3,15 @@ package fp
 import "reflect"
 
 func (q *stream) GroupBy(fn interface{}) KVStream {
+	fn = resolveSelector(fn, q.expectElemTyp)
+	assertKeyFunc("GroupBy", fn, q.expectElemTyp)
 	keyTyp := reflect.TypeOf(fn).Out(0)
 	valTyp := reflect.SliceOf(q.expectElemTyp)
 
-	iter := q.iter
-	return newKvStream(newCtx(q.ctx), keyTyp, valTyp, func() reflect.Value {
-		table := reflect.MakeMap(reflect.MapOf(keyTyp, valTyp))
+	ctx := q.derivedCtx()
+	iter := q.upstream(ctx)
+	return newKvStream(ctx, keyTyp, valTyp, func() kvTable {
+		table := newKVTable(keyTyp, valTyp)
 		fnVal := reflect.ValueOf(fn)
 		for {
 			val, ok := iter()
@@ -16,12 +19,11 @@ func (q *stream) GroupBy(fn interface{}) KVStream {
 				break
 			}
 			key := fnVal.Call([]reflect.Value{val})[0]
-			slice := table.MapIndex(key)
-			if !slice.IsValid() {
+			slice, ok := table.Get(key)
+			if !ok {
 				slice = reflect.Zero(valTyp)
 			}
-			slice = reflect.Append(slice, val)
-			table.SetMapIndex(key, slice)
+			table.Set(key, reflect.Append(slice, val))
 		}
 		return table
 	})
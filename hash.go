@@ -0,0 +1,348 @@
+package fp
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// Hasher could be implemented by element or key types which are not hashable by go map,
+// equal values (by reflect.DeepEqual) must have the same hash. Comparable types always use go map,
+// so pointer keys keep their identity even if they implement Hasher
+type Hasher interface {
+	Hash() uint64
+}
+
+var hasherType = reflect.TypeOf((*Hasher)(nil)).Elem()
+
+/* kvTable is the storage of KVStream, go map for hashable keys and hashTable for others */
+type kvTable interface {
+	Len() int
+	Get(k reflect.Value) (reflect.Value, bool)
+	Set(k, v reflect.Value)
+	/* Iter iterate entries, ok is false when iteration ends */
+	Iter() func() (k, v reflect.Value, ok bool)
+	/* Map convert table to go map, false if keys can't be map keys */
+	Map() (reflect.Value, bool)
+}
+
+func newKVTable(k, v reflect.Type) kvTable {
+	if isHashable(k) {
+		return mapTable{m: reflect.MakeMap(reflect.MapOf(k, v))}
+	} else if mayBeHashable(k) {
+		return &mixedTable{m: mapTable{m: reflect.MakeMap(reflect.MapOf(k, v))}, h: newHashTable(k, v)}
+	}
+	return newHashTable(k, v)
+}
+
+/* isHashable tell if every value of typ could be go map key, interface is not since its dynamic value may not */
+func isHashable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Interface, reflect.Slice, reflect.Map, reflect.Func:
+		return false
+	case reflect.Array:
+		return isHashable(typ.Elem())
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if !isHashable(typ.Field(i).Type) {
+				return false
+			}
+		}
+	}
+	return typ.Comparable()
+}
+
+/* mayBeHashable tell if some values of typ could be go map key, e.g. interfaces, hashableValue decides for each value */
+func mayBeHashable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Slice, reflect.Map, reflect.Func:
+		return false
+	case reflect.Array:
+		return mayBeHashable(typ.Elem())
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if !mayBeHashable(typ.Field(i).Type) {
+				return false
+			}
+		}
+	}
+	return typ.Comparable()
+}
+
+/* hashableValue tell if v could be go map key by its dynamic values */
+func hashableValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface:
+		return v.IsNil() || hashableValue(v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !hashableValue(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !hashableValue(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return isHashable(v.Type())
+}
+
+type mapTable struct {
+	m reflect.Value
+}
+
+func (t mapTable) Len() int { return t.m.Len() }
+
+func (t mapTable) Get(k reflect.Value) (reflect.Value, bool) {
+	v := t.m.MapIndex(k)
+	return v, v.IsValid()
+}
+
+func (t mapTable) Set(k, v reflect.Value) { t.m.SetMapIndex(k, v) }
+
+func (t mapTable) Iter() func() (reflect.Value, reflect.Value, bool) {
+	iter := t.m.MapRange()
+	return func() (reflect.Value, reflect.Value, bool) {
+		if iter.Next() {
+			return iter.Key(), iter.Value(), true
+		}
+		return reflect.Value{}, reflect.Value{}, false
+	}
+}
+
+func (t mapTable) Map() (reflect.Value, bool) { return t.m, true }
+
+/* hashTable bucket entries by structural hash and compare keys by reflect.DeepEqual, entries keep insertion order */
+type hashTable struct {
+	keyType, valType reflect.Type
+	buckets          map[uint64][]int
+	keys, vals       []reflect.Value
+}
+
+func newHashTable(k, v reflect.Type) *hashTable {
+	return &hashTable{keyType: k, valType: v, buckets: make(map[uint64][]int)}
+}
+
+func (t *hashTable) Len() int { return len(t.keys) }
+
+func (t *hashTable) find(k reflect.Value) (uint64, int) {
+	h := hashOf(k)
+	for _, i := range t.buckets[h] {
+		if reflect.DeepEqual(t.keys[i].Interface(), k.Interface()) {
+			return h, i
+		}
+	}
+	return h, -1
+}
+
+func (t *hashTable) Get(k reflect.Value) (reflect.Value, bool) {
+	if _, i := t.find(k); i >= 0 {
+		return t.vals[i], true
+	}
+	return reflect.Value{}, false
+}
+
+func (t *hashTable) Set(k, v reflect.Value) {
+	h, i := t.find(k)
+	if i >= 0 {
+		t.vals[i] = v
+		return
+	}
+	t.buckets[h] = append(t.buckets[h], len(t.keys))
+	t.keys = append(t.keys, k)
+	t.vals = append(t.vals, v)
+}
+
+func (t *hashTable) Iter() func() (reflect.Value, reflect.Value, bool) {
+	var i int
+	return func() (reflect.Value, reflect.Value, bool) {
+		if i >= len(t.keys) {
+			return reflect.Value{}, reflect.Value{}, false
+		}
+		i++
+		return t.keys[i-1], t.vals[i-1], true
+	}
+}
+
+func (t *hashTable) Map() (m reflect.Value, ok bool) {
+	if !t.keyType.Comparable() {
+		return reflect.Value{}, false
+	}
+	/* dynamic value of interface key may be unhashable */
+	defer func() {
+		if r := recover(); r != nil {
+			m, ok = reflect.Value{}, false
+		}
+	}()
+	m = reflect.MakeMapWithSize(reflect.MapOf(t.keyType, t.valType), len(t.keys))
+	for i, k := range t.keys {
+		m.SetMapIndex(k, t.vals[i])
+	}
+	return m, true
+}
+
+/* mixedTable keep keys in go map if their dynamic values are hashable, others in hashTable */
+type mixedTable struct {
+	m mapTable
+	h *hashTable
+}
+
+func (t *mixedTable) Len() int { return t.m.Len() + t.h.Len() }
+
+func (t *mixedTable) Get(k reflect.Value) (reflect.Value, bool) {
+	if hashableValue(k) {
+		return t.m.Get(k)
+	}
+	return t.h.Get(k)
+}
+
+func (t *mixedTable) Set(k, v reflect.Value) {
+	if hashableValue(k) {
+		t.m.Set(k, v)
+	} else {
+		t.h.Set(k, v)
+	}
+}
+
+func (t *mixedTable) Iter() func() (reflect.Value, reflect.Value, bool) {
+	mi, hi := t.m.Iter(), t.h.Iter()
+	return func() (reflect.Value, reflect.Value, bool) {
+		if k, v, ok := mi(); ok {
+			return k, v, true
+		}
+		return hi()
+	}
+}
+
+func (t *mixedTable) Map() (reflect.Value, bool) {
+	if t.h.Len() == 0 {
+		return t.m.Map()
+	}
+	return reflect.Value{}, false
+}
+
+/* valueSet return function adding value to set, it returns false if value already exists */
+func valueSet(typ reflect.Type) func(reflect.Value) bool {
+	if isHashable(typ) {
+		set := make(map[interface{}]struct{})
+		return func(v reflect.Value) bool {
+			key := v.Interface()
+			if _, ok := set[key]; ok {
+				return false
+			}
+			set[key] = struct{}{}
+			return true
+		}
+	}
+	var table kvTable = newHashTable(typ, nil)
+	if mayBeHashable(typ) {
+		table = &mixedTable{m: mapTable{m: reflect.MakeMap(reflect.MapOf(typ, boolType))}, h: newHashTable(typ, nil)}
+	}
+	return func(v reflect.Value) bool {
+		if _, ok := table.Get(v); ok {
+			return false
+		}
+		table.Set(v, reflect.ValueOf(true))
+		return true
+	}
+}
+
+/* hashOf structural hash consistent with reflect.DeepEqual */
+func hashOf(v reflect.Value) uint64 {
+	h := fnv.New64a()
+	writeHash(h, v, 0)
+	return h.Sum64()
+}
+
+type hashWriter interface {
+	Write([]byte) (int, error)
+}
+
+func writeHash(h hashWriter, v reflect.Value, depth int) {
+	if !v.IsValid() || depth > 16 {
+		h.Write([]byte{0})
+		return
+	}
+	if v.CanInterface() && v.Type().Implements(hasherType) && (v.Kind() != reflect.Ptr || !v.IsNil()) {
+		writeUint(h, v.Interface().(Hasher).Hash())
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.Write([]byte{1})
+		} else {
+			h.Write([]byte{2})
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(h, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeFloat(h, real(v.Complex()))
+		writeFloat(h, imag(v.Complex()))
+	case reflect.String:
+		h.Write([]byte(v.String()))
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			h.Write([]byte{0})
+			return
+		}
+		writeUint(h, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			writeHash(h, v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		/* order independent */
+		var sum uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			sum += hashOf(iter.Key()) ^ (hashOf(iter.Value()) * 31)
+		}
+		writeUint(h, uint64(v.Len()))
+		writeUint(h, sum)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeHash(h, v.Field(i), depth+1)
+		}
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			h.Write([]byte{0})
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			depth++
+		}
+		/* interface is transparent, so that element of []interface{} and its dynamic value hash the same */
+		writeHash(h, v.Elem(), depth)
+	default:
+		/* chan, func: DeepEqual compares them by identity */
+		h.Write([]byte(fmt.Sprintf("%v:%x", v.Type(), v.Pointer())))
+	}
+}
+
+/* writeFloat hash -0 as 0 since they are equal */
+func writeFloat(h hashWriter, f float64) {
+	if f == 0 {
+		f = 0
+	}
+	writeUint(h, math.Float64bits(f))
+}
+
+func writeUint(h hashWriter, u uint64) {
+	var b [8]byte
+	for i := range b {
+		b[i] = byte(u >> (8 * i))
+	}
+	h.Write(b[:])
+}
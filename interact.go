@@ -30,7 +30,9 @@ func (q *stream) InteractBy(other Stream, keyfn interface{}) Stream {
 	}
 	var once sync.Once
 	var set KVStream
-	keyfnval := reflect.ValueOf(keyfn)
+	/* keyfn may be selector, which is resolved against other stream by ToSetBy */
+	assertKeyFunc("InteractBy", resolveSelector(keyfn, q.expectElemTyp), q.expectElemTyp)
+	keyfnval := reflect.ValueOf(resolveSelector(keyfn, q.expectElemTyp))
 	getSet := func() KVStream {
 		once.Do(func() {
 			set = other.ToSetBy(keyfn)
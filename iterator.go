@@ -1,7 +1,82 @@
 package fp
 
-import "reflect"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
 
 type iterator func() (reflect.Value, bool)
 
 type middleware func(iterator) iterator
+
+// Iterator pull elements of stream one by one
+type Iterator interface {
+	// Next advance to next element, return false when stream is exhausted or failed
+	Next() bool
+	// Value of current element
+	Value() interface{}
+	// Scan current element into ptr
+	Scan(ptr interface{}) error
+	// Err of stream so far, it would not run the stream
+	Err() error
+	// Close abandon the stream
+	Close() error
+}
+
+func (q *stream) Iterator() Iterator {
+	q = q.consumer()
+	return &streamIterator{q: q, next: q.iter}
+}
+
+type streamIterator struct {
+	q    *stream
+	next iterator
+	val  reflect.Value
+	done bool
+}
+
+func (it *streamIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	val, ok := it.next()
+	if !ok {
+		it.done, it.val = true, reflect.Value{}
+		it.q.finish(true)
+		return false
+	}
+	it.val = val
+	return true
+}
+
+func (it *streamIterator) Value() interface{} {
+	if !it.val.IsValid() {
+		return nil
+	}
+	return it.val.Interface()
+}
+
+func (it *streamIterator) Scan(ptr interface{}) error {
+	if !it.val.IsValid() {
+		return errors.New("fp: no current element to scan")
+	}
+	dst := reflect.ValueOf(ptr)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return errors.New(`fp: dst must be pointer`)
+	}
+	if !it.val.Type().AssignableTo(dst.Elem().Type()) {
+		return fmt.Errorf("fp: can not scan %v into %v", it.val.Type(), dst.Type())
+	}
+	dst.Elem().Set(it.val)
+	return nil
+}
+
+func (it *streamIterator) Err() error {
+	return it.q.ctx.Err()
+}
+
+func (it *streamIterator) Close() error {
+	it.done, it.val = true, reflect.Value{}
+	return it.q.Close()
+}
@@ -1,11 +1,13 @@
 package fp
 
 import (
+	"fmt"
 	"reflect"
 	"sync/atomic"
 )
 
 type KVStream interface {
+	seqKVStream
 	// Foreach element of object
 	// fn should be func(key_type,element_type)
 	Foreach(fn interface{}) KVStream
@@ -30,34 +32,43 @@ type KVStream interface {
 	Run()
 	// To dst ptr
 	To(dstPtr interface{}) error
+	// SafeMode recover panics of user functions, the panic is stored as *PanicError
+	SafeMode() KVStream
 }
 
 type kvStream struct {
-	getMap           func() reflect.Value
+	getTable         func() kvTable
 	keyType, valType reflect.Type
 	ctx              context
+	/* pairs pull k-v pairs of iter.Seq2 directly until table is built */
+	pairs func() (reflect.Value, reflect.Value, bool)
 }
 
 func KVStreamOf(m interface{}) KVStream {
 	if s, ok := m.(KVSource); ok {
 		return KVStreamOfSource(s)
 	}
+	if val := reflect.ValueOf(m); isSeqFunction(val.Type(), 2) {
+		if s, ok := kvStreamOfSeq2(val); ok {
+			return s
+		}
+	}
 	if reflect.TypeOf(m).Kind() != reflect.Map {
 		panic("argument should be map")
 	}
 	tp := reflect.TypeOf(m)
-	return newKvStream(nil, tp.Key(), tp.Elem(), func() reflect.Value {
-		return reflect.ValueOf(m)
+	return newKvStream(nil, tp.Key(), tp.Elem(), func() kvTable {
+		return mapTable{m: reflect.ValueOf(m)}
 	})
 }
 
 func KVStreamOfSource(s KVSource) KVStream {
 	keyType, valType := s.ElemType()
-	return newKvStream(nil, keyType, valType, func() reflect.Value {
-		table := reflect.MakeMap(reflect.MapOf(keyType, valType))
+	return newKvStream(nil, keyType, valType, func() kvTable {
+		table := newKVTable(keyType, valType)
 		for {
 			if k, v, ok := s.Next(); ok {
-				table.SetMapIndex(k, v)
+				table.Set(k, v)
 			} else {
 				break
 			}
@@ -66,53 +77,99 @@ func KVStreamOfSource(s KVSource) KVStream {
 	})
 }
 
+/* kvStreamOfSeq2 pull iter.Seq2 lazily, pairs are collected into table only when a table op needs them */
+func kvStreamOfSeq2(seq reflect.Value) (KVStream, bool) {
+	yieldTyp := seq.Type().In(0)
+	keyType, valType := yieldTyp.In(0), yieldTyp.In(1)
+	ctx := newCtx(nil)
+	_, it, ok := seqIter(ctx, seq)
+	if !ok {
+		return nil, false
+	}
+	pairs := func() (reflect.Value, reflect.Value, bool) {
+		val, ok := it()
+		if !ok {
+			return reflect.Value{}, reflect.Value{}, false
+		}
+		t := val.Interface().(Tuple)
+		return convertTo(reflect.ValueOf(t.E1), keyType), convertTo(reflect.ValueOf(t.E2), valType), true
+	}
+	var s *kvStream
+	s = newKvStream(ctx, keyType, valType, func() kvTable {
+		table := newKVTable(keyType, valType)
+		for k, v, ok := pairs(); ok; k, v, ok = pairs() {
+			table.Set(k, v)
+		}
+		s.pairs = nil
+		return table
+	})
+	s.pairs = pairs
+	return s, true
+}
+
+/* iter pull pairs from source while table is not built, so duplicate keys are kept and infinite source works */
+func (obj *kvStream) iter() func() (reflect.Value, reflect.Value, bool) {
+	if obj.pairs != nil {
+		return obj.pairs
+	}
+	return obj.getTable().Iter()
+}
+
 func (obj *kvStream) Foreach(fn interface{}) KVStream {
+	assertFunc("KVStream.Foreach", fn, sig(types(obj.keyType, obj.valType)))
 	fnVal := reflect.ValueOf(fn)
-	getMap := obj.getMap
-	return newKvStream(newCtx(obj.ctx), obj.keyType, obj.valType, func() reflect.Value {
-		mp := getMap()
-		iter := mp.MapRange()
-		for iter.Next() {
-			fnVal.Call([]reflect.Value{iter.Key(), iter.Value()})
+	getTable := obj.getTable
+	return newKvStream(newCtx(obj.ctx), obj.keyType, obj.valType, func() kvTable {
+		table := getTable()
+		next := table.Iter()
+		for k, v, ok := next(); ok; k, v, ok = next() {
+			fnVal.Call([]reflect.Value{k, v})
 		}
-		return mp
+		return table
 	})
 }
 
 func (obj *kvStream) Map(fn interface{}) KVStream {
+	kv := types(obj.keyType, obj.valType)
+	assertFunc("KVStream.Map", fn, sig(kv, nil, nil), sig(kv, nil, nil, errType))
 	fnTyp := reflect.TypeOf(fn)
 	fnVal := reflect.ValueOf(fn)
-	getMap := obj.getMap
+	getTable := obj.getTable
 	hasErr := fnVal.Type().NumOut() == 3 && fnVal.Type().Out(2).ConvertibleTo(errType)
 	ctx := newCtx(obj.ctx)
-	return newKvStream(ctx, fnTyp.Out(0), fnTyp.Out(1), func() reflect.Value {
-		iter := getMap().MapRange()
-		table := reflect.MakeMap(reflect.MapOf(fnTyp.Out(0), fnTyp.Out(1)))
-		for iter.Next() {
-			out := fnVal.Call([]reflect.Value{iter.Key(), iter.Value()})
+	return newKvStream(ctx, fnTyp.Out(0), fnTyp.Out(1), func() kvTable {
+		next := getTable().Iter()
+		table := newKVTable(fnTyp.Out(0), fnTyp.Out(1))
+		for k, v, ok := next(); ok; k, v, ok = next() {
+			out := fnVal.Call([]reflect.Value{k, v})
 			if !hasErr {
 			} else if err := obj.asErr(out[2].Interface()); err != nil {
 				ctx.SetErr(err)
 				break
 			}
-			table.SetMapIndex(out[0], out[1])
+			table.Set(out[0], out[1])
 		}
 		return table
 	})
 }
 
 func (obj *kvStream) ZipMap(fn interface{}) Stream {
+	kv := types(obj.keyType, obj.valType)
+	assertFunc("KVStream.ZipMap", fn, sig(kv, nil), sig(kv, nil, errType))
 	fnVal := reflect.ValueOf(fn)
-	var iter *reflect.MapIter
+	var next func() (reflect.Value, reflect.Value, bool)
 	var done bool
 	ctx := newCtx(obj.ctx)
 	hasErr := fnVal.Type().NumOut() == 2 && fnVal.Type().Out(1).ConvertibleTo(errType)
 	return newStream(ctx, fnVal.Type().Out(0), func() (reflect.Value, bool) {
-		if iter == nil {
-			iter = obj.getMap().MapRange()
+		if next == nil {
+			next = obj.iter()
+		}
+		if done {
+			return reflect.Value{}, false
 		}
-		if !done && iter.Next() {
-			out := fnVal.Call([]reflect.Value{iter.Key(), iter.Value()})
+		if k, v, ok := next(); ok {
+			out := fnVal.Call([]reflect.Value{k, v})
 			if !hasErr {
 			} else if err := obj.asErr(out[1].Interface()); err != nil {
 				ctx.SetErr(err)
@@ -128,14 +185,14 @@ func (obj *kvStream) ZipMap(fn interface{}) Stream {
 
 // Filter kv pair
 func (obj *kvStream) Filter(fn interface{}) KVStream {
+	assertPredicate("KVStream.Filter", fn, obj.keyType, obj.valType)
 	fnVal := reflect.ValueOf(fn)
-	return newKvStream(newCtx(obj.ctx), obj.keyType, obj.valType, func() reflect.Value {
-		table := reflect.MakeMap(reflect.MapOf(obj.keyType, obj.valType))
-		iter := obj.getMap().MapRange()
-		for iter.Next() {
-			k, v := iter.Key(), iter.Value()
-			if ok := fnVal.Call([]reflect.Value{k, v})[0].Bool(); ok {
-				table.SetMapIndex(k, v)
+	return newKvStream(newCtx(obj.ctx), obj.keyType, obj.valType, func() kvTable {
+		table := newKVTable(obj.keyType, obj.valType)
+		next := obj.getTable().Iter()
+		for k, v, ok := next(); ok; k, v, ok = next() {
+			if yes := fnVal.Call([]reflect.Value{k, v})[0].Bool(); yes {
+				table.Set(k, v)
 			}
 		}
 		return table
@@ -144,14 +201,14 @@ func (obj *kvStream) Filter(fn interface{}) KVStream {
 
 // Reject kv pair
 func (obj *kvStream) Reject(fn interface{}) KVStream {
+	assertPredicate("KVStream.Reject", fn, obj.keyType, obj.valType)
 	fnVal := reflect.ValueOf(fn)
-	return newKvStream(newCtx(obj.ctx), obj.keyType, obj.valType, func() reflect.Value {
-		table := reflect.MakeMap(reflect.MapOf(obj.keyType, obj.valType))
-		iter := obj.getMap().MapRange()
-		for iter.Next() {
-			k, v := iter.Key(), iter.Value()
-			if ok := fnVal.Call([]reflect.Value{k, v})[0].Bool(); !ok {
-				table.SetMapIndex(k, v)
+	return newKvStream(newCtx(obj.ctx), obj.keyType, obj.valType, func() kvTable {
+		table := newKVTable(obj.keyType, obj.valType)
+		next := obj.getTable().Iter()
+		for k, v, ok := next(); ok; k, v, ok = next() {
+			if yes := fnVal.Call([]reflect.Value{k, v})[0].Bool(); !yes {
+				table.Set(k, v)
 			}
 		}
 		return table
@@ -164,45 +221,45 @@ func (obj *kvStream) Contains(key interface{}) bool {
 	if kval.Type() != obj.keyType && kval.Type().ConvertibleTo(obj.keyType) {
 		kval = kval.Convert(obj.keyType)
 	}
-	if ele := obj.getMap().MapIndex(kval); !ele.IsValid() {
-		return false
-	}
-	return true
+	table := obj.getTable()
+	obj.finish()
+	_, ok := table.Get(kval)
+	return ok
 }
 
 // Keys of object
 func (obj *kvStream) Keys() Stream {
-	var iter *reflect.MapIter
-	var done bool
+	var next func() (reflect.Value, reflect.Value, bool)
 	return newStream(newCtx(obj.ctx), obj.keyType, func() (reflect.Value, bool) {
-		if iter == nil {
-			iter = obj.getMap().MapRange()
+		if next == nil {
+			next = obj.iter()
 		}
-		if !done && iter.Next() {
-			return iter.Key(), true
+		k, v, ok := next()
+		if !ok {
+			next = func() (reflect.Value, reflect.Value, bool) { return k, v, false }
 		}
-		done = true
-		return reflect.Value{}, false
+		return k, ok
 	})
 }
 
 // Values of object
 func (obj *kvStream) Values() Stream {
-	var iter *reflect.MapIter
-	var done bool
+	var next func() (reflect.Value, reflect.Value, bool)
 	return newStream(newCtx(obj.ctx), obj.valType, func() (reflect.Value, bool) {
-		if iter == nil {
-			iter = obj.getMap().MapRange()
+		if next == nil {
+			next = obj.iter()
 		}
-		if !done && iter.Next() {
-			return iter.Value(), true
+		k, v, ok := next()
+		if !ok {
+			next = func() (reflect.Value, reflect.Value, bool) { return k, v, false }
 		}
-		done = true
-		return reflect.Value{}, false
+		return v, ok
 	})
 }
 
 func (l *kvStream) Result() interface{} {
+	l.getTable()
+	l.finish()
 	return l.getRelut().Result()
 }
 
@@ -211,6 +268,8 @@ func (l *kvStream) Run() {
 }
 
 func (l *kvStream) To(ptr interface{}) error {
+	l.getTable()
+	l.finish()
 	val := l.getRelut()
 	err := val.err
 	val.err = nil
@@ -219,14 +278,20 @@ func (l *kvStream) To(ptr interface{}) error {
 }
 
 func (l *kvStream) getRelut() Value {
+	mp, ok := l.getTable().Map()
+	if !ok {
+		return Value{err: fmt.Errorf("fp: keys of %v can not be go map keys, use Keys/Values/ZipMap instead", l.keyType)}
+	}
 	val := Value{
 		typ: reflect.MapOf(l.keyType, l.valType),
-		val: l.getMap(),
+		val: mp,
 	}
 	if !val.val.IsValid() || val.val.IsNil() {
 		val.val = reflect.MakeMap(val.typ)
 	}
-	val.err = l.ctx.Err()
+	if err := l.ctx.Err(); err != nil {
+		val.err = err
+	}
 	return val
 }
 
@@ -239,25 +304,29 @@ func (obj *kvStream) asErr(out interface{}) error {
 
 // Size of map
 func (obj *kvStream) Size() int {
-	return obj.getMap().Len()
+	size := obj.getTable().Len()
+	obj.finish()
+	return size
 }
 
-func newKvStream(ctx context, k, v reflect.Type, getmp func() reflect.Value) *kvStream {
+func newKvStream(ctx context, k, v reflect.Type, getTable func() kvTable) *kvStream {
 	if ctx == nil {
 		ctx = newCtx(nil)
 	}
 	if ctx.Err() != nil {
-		getmp = func() reflect.Value {
-			return reflect.MakeMap(reflect.MapOf(k, v))
+		getTable = func() kvTable {
+			return newKVTable(k, v)
 		}
+	} else if ctx.Safe() {
+		getTable = recoverTable(ctx, k, v, getTable)
 	}
-	return &kvStream{ctx: ctx, keyType: k, valType: v, getMap: getMapOnce(getmp)}
+	return &kvStream{ctx: ctx, keyType: k, valType: v, getTable: getTableOnce(getTable)}
 }
 
-func getMapOnce(f func() reflect.Value) func() reflect.Value {
+func getTableOnce(f func() kvTable) func() kvTable {
 	var flag int32
-	var v reflect.Value
-	return func() reflect.Value {
+	var v kvTable
+	return func() kvTable {
 		if atomic.CompareAndSwapInt32(&flag, 0, 1) {
 			v = f()
 		}
@@ -3,7 +3,6 @@ package fp
 import (
 	"errors"
 	"fmt"
-	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -183,7 +182,7 @@ func (suite *KVStreamTestSuite) TestResultMapAlwayNonNil() {
 	/* with error */
 	ctx := newCtx(nil)
 	ctx.SetErr(errors.New(""))
-	s := newKvStream(ctx, boolType, boolType, func() reflect.Value { return reflect.Value{} })
+	s := newKvStream(ctx, boolType, boolType, func() kvTable { return nil })
 	var mp map[bool]bool
 	err := s.To(&mp)
 	suite.Len(mp, 0)
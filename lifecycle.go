@@ -0,0 +1,117 @@
+package fp
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+/* lifecycle is shared by all stages of a stream, it runs hooks and closes resources exactly once */
+type lifecycle struct {
+	state      int32
+	closers    []io.Closer
+	onComplete []func()
+	onError    []func(error)
+	finally    []func()
+	err        error
+}
+
+func (lc *lifecycle) addCloser(c io.Closer) {
+	lc.closers = append(lc.closers, c)
+}
+
+/* finish close resources then run hooks, err is the stream error, drained means stream is exhausted */
+func (lc *lifecycle) finish(err error, drained bool) error {
+	if atomic.CompareAndSwapInt32(&lc.state, 0, 1) {
+		for i := len(lc.closers) - 1; i >= 0; i-- {
+			if cerr := lc.closers[i].Close(); cerr != nil && lc.err == nil {
+				lc.err = cerr
+			}
+		}
+		if err == nil {
+			err = lc.err
+		}
+		if err != nil {
+			for _, fn := range lc.onError {
+				fn(err)
+			}
+		} else if drained {
+			for _, fn := range lc.onComplete {
+				fn()
+			}
+		}
+		for _, fn := range lc.finally {
+			fn()
+		}
+	}
+	return lc.err
+}
+
+// Using create stream by source opened by open, closer is closed when stream is exhausted, failed or closed
+func Using(open func() (Source, io.Closer, error)) Stream {
+	ctx := newCtx(nil)
+	source, closer, err := open()
+	if closer != nil {
+		ctx.hooks().addCloser(closer)
+	}
+	if err != nil {
+		source = newNilSource()
+		ctx.SetErr(err)
+	}
+	return newStream(ctx, source.ElemType(), source.Next)
+}
+
+func (q *stream) OnComplete(fn func()) Stream {
+	lc := q.ctx.hooks()
+	lc.onComplete = append(lc.onComplete, fn)
+	return q
+}
+
+func (q *stream) OnError(fn func(error)) Stream {
+	lc := q.ctx.hooks()
+	lc.onError = append(lc.onError, fn)
+	return q
+}
+
+func (q *stream) Finally(fn func()) Stream {
+	lc := q.ctx.hooks()
+	lc.finally = append(lc.finally, fn)
+	return q
+}
+
+func (q *stream) Close() error {
+	return q.finish(false)
+}
+
+/* finish stream, error of closing resources would be stored if stream has no error */
+func (q *stream) finish(drained bool) error {
+	err := q.ctx.hooks().finish(q.ctx.Err(), drained)
+	if err != nil && q.ctx.Err() == nil {
+		q.ctx.SetErr(err)
+	}
+	return err
+}
+
+func (obj *kvStream) finish() {
+	if err := obj.ctx.hooks().finish(obj.ctx.Err(), true); err != nil && obj.ctx.Err() == nil {
+		obj.ctx.SetErr(err)
+	}
+}
+
+type closeFunc func()
+
+func (fn closeFunc) Close() error {
+	fn()
+	return nil
+}
+
+/* reportFunc is closer reporting error of stream when it finishes */
+type reportFunc func() error
+
+func (fn reportFunc) Close() error { return fn() }
+
+/* register source as closer of ctx if it could be closed */
+func closeWith(ctx context, source interface{}) {
+	if c, ok := source.(io.Closer); ok {
+		ctx.hooks().addCloser(c)
+	}
+}
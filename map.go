@@ -1,44 +1,147 @@
 package fp
 
-import "reflect"
+import (
+	"reflect"
+	"time"
+)
 
-func (q *stream) Map(fn interface{}) Stream {
+type ErrorStrategy int
+
+const (
+	// StopOnError keep first error and stop stream, this is the default strategy
+	StopOnError ErrorStrategy = iota
+	// SkipErrors drop elements whose map function failed
+	SkipErrors
+	// CollectErrors drop elements whose map function failed, errors are reported as MultiError when stream ends,
+	// or when it is finished early by terminal ops like First or Take(n).Ints()
+	CollectErrors
+)
+
+type MapOption func(*mapOption)
+
+type mapOption struct {
+	onError    ErrorStrategy
+	deadLetter interface{}
+	retries    int
+	backoff    Backoff
+	retryable  func(error) bool
+	timeout    time.Duration
+	clock      Clock
+}
+
+// OnError set error strategy of map function
+func OnError(strategy ErrorStrategy) MapOption {
+	return func(opt *mapOption) { opt.onError = strategy }
+}
+
+// OnDeadLetter route failed elements to dl and keep stream going, dl should be func(element_type, error) or func(*ElementError)
+// it's validated against element type when Map is called
+func OnDeadLetter(dl interface{}) MapOption {
+	return func(opt *mapOption) { opt.deadLetter = dl }
+}
+
+/* deadLetterOf validate dead letter function dl against element type and adapt it to callback of failed element */
+func deadLetterOf(op string, dl interface{}, elemTyp reflect.Type) func(reflect.Value, *ElementError) {
+	if f, ok := dl.(func(*ElementError)); ok {
+		return func(_ reflect.Value, e *ElementError) { f(e) }
+	}
+	assertFunc(op, dl, sig(types(elemTyp, errType)), sig(types(reflect.TypeOf(&ElementError{}))))
+	dlVal := reflect.ValueOf(dl)
+	if dlVal.Type().NumIn() == 1 {
+		return func(_ reflect.Value, e *ElementError) { dlVal.Call([]reflect.Value{reflect.ValueOf(e)}) }
+	}
+	return func(val reflect.Value, e *ElementError) {
+		dlVal.Call([]reflect.Value{val, reflect.ValueOf(&e.Err).Elem()})
+	}
+}
+
+func newMapOption(opts []MapOption) *mapOption {
+	opt := &mapOption{}
+	for _, fn := range opts {
+		fn(opt)
+	}
+	return opt
+}
+
+func (q *stream) Map(fn interface{}, opts ...MapOption) Stream {
+	fn = resolveSelector(fn, q.expectElemTyp)
+	assertMapper("Map", fn, q.expectElemTyp)
 	fnTyp := reflect.TypeOf(fn)
-	fnVal := reflect.ValueOf(fn)
-	ctx := newCtx(q.ctx)
-	mapFn := func(in reflect.Value) (reflect.Value, bool) {
-		return fnVal.Call([]reflect.Value{in})[0], true
+	opt := newMapOption(opts)
+	fnVal := opt.guard("Map", reflect.ValueOf(fn))
+	var deadLetter func(reflect.Value, *ElementError)
+	if opt.deadLetter != nil {
+		deadLetter = deadLetterOf("Map", opt.deadLetter, q.expectElemTyp)
+	}
+	ctx := q.derivedCtx()
+	call, borrowed := slotMapperOf(fn)
+	mapFn := func(in reflect.Value) (reflect.Value, bool, error) {
+		return call(in), true, nil
 	}
+	/* argument slice is reused across elements, Call copies it */
+	args := make([]reflect.Value, 1)
 	if fnTyp.NumOut() == 2 && fnTyp.Out(1) == boolType {
-		mapFn = func(in reflect.Value) (reflect.Value, bool) {
-			out := fnVal.Call([]reflect.Value{in})
-			return out[0], out[1].Bool()
+		mapFn = func(in reflect.Value) (reflect.Value, bool, error) {
+			args[0] = in
+			out := fnVal.Call(args)
+			return out[0], out[1].Bool(), nil
 		}
 	} else if fnTyp.NumOut() == 2 && fnTyp.Out(1).ConvertibleTo(errType) {
-		mapFn = func(in reflect.Value) (reflect.Value, bool) {
-			out := fnVal.Call([]reflect.Value{in})
-			err := out[1].Interface()
-			if err != nil && err.(error) != nil {
-				ctx.SetErr(err.(error))
+		mapFn = func(in reflect.Value) (reflect.Value, bool, error) {
+			args[0] = in
+			out := fnVal.Call(args)
+			if err := out[1].Interface(); err != nil && err.(error) != nil {
+				return out[0], false, err.(error)
 			}
-			return out[0], err == nil || err.(error) == nil
+			return out[0], true, nil
 		}
-	} else if fnTyp.NumOut() == 1 {
-	} else {
-		panic("Map function must be func(element_type) another_type or func(element_type) (another_type,error/bool), now " + fnTyp.String())
-	}
-
-	return newStream(ctx, fnTyp.Out(0), q.iter, func(next iterator) iterator {
-		return func() (reflect.Value, bool) {
-			for {
-				if val, ok := next(); !ok {
-					return reflect.Value{}, false
-				} else if val, ok = mapFn(val); ok {
-					return val, true
-				} else if ctx.Err() != nil {
-					return val, false
-				}
-			}
+	}
+
+	var index int
+	var errs []error
+	report := func() error {
+		if len(errs) == 0 {
+			return nil
 		}
+		err := &MultiError{Errors: errs}
+		errs = nil
+		ctx.SetErr(err)
+		return err
+	}
+	if opt.onError == CollectErrors {
+		/* stream may finish before it is drained, e.g. by First or Take */
+		ctx.hooks().addCloser(reportFunc(report))
+	}
+	return q.fuse(ctx, fnTyp.Out(0), stage{
+		run: func(val reflect.Value) (reflect.Value, stageResult) {
+			index++
+			out, ok, err := mapFn(val)
+			if err == nil && ok {
+				return out, emitElem
+			} else if err == nil {
+			} else if deadLetter != nil {
+				deadLetter(val, &ElementError{Stage: "Map", Index: index - 1, Element: val.Interface(), Err: err})
+				return out, skipElem
+			} else if opt.onError == SkipErrors {
+				return out, skipElem
+			} else if opt.onError == CollectErrors {
+				errs = append(errs, &ElementError{Stage: "Map", Index: index - 1, Element: val.Interface(), Err: err})
+				return out, skipElem
+			} else {
+				ctx.SetErr(err)
+			}
+			if ctx.Err() != nil {
+				return out, stopStream
+			}
+			return out, skipElem
+		},
+		borrowed: borrowed && fnTyp.NumOut() == 1,
+		end:      func() { report() },
 	})
 }
+
+func (q *stream) MapOrDeadLetter(fn interface{}, dl interface{}) Stream {
+	/* nil dl is rejected here rather than ignored by Map */
+	deadLetterOf("MapOrDeadLetter", dl, q.expectElemTyp)
+	return q.Map(fn, OnDeadLetter(dl))
+}
@@ -0,0 +1,69 @@
+package fp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+/* Matcher case function should be func(type) any or func(type) (any, error), all cases return the same type */
+type Matcher struct {
+	cases  []reflect.Value
+	outTyp reflect.Type
+}
+
+// Match build a function dispatching on dynamic type of input, use it like
+// Match().Case(func(e *Created) R).Case(func(e *Deleted) R).Default(func(interface{}) R)
+func Match() Matcher {
+	return Matcher{}
+}
+
+func (m Matcher) Case(fn interface{}) Matcher {
+	m.assert("Match.Case", fn, nil)
+	if m.outTyp == nil {
+		m.outTyp = reflect.TypeOf(fn).Out(0)
+	}
+	m.cases = append(append([]reflect.Value{}, m.cases...), reflect.ValueOf(fn))
+	return m
+}
+
+// Default build func(interface{}) (R, error), fn handles input matching no case
+func (m Matcher) Default(fn interface{}) interface{} {
+	m.assert("Match.Default", fn, anyType)
+	return m.build(reflect.ValueOf(fn))
+}
+
+// Build func(interface{}) (R, error), input matching no case fails with ErrNoMatch
+func (m Matcher) Build() interface{} {
+	return m.build(reflect.Value{})
+}
+
+func (m Matcher) assert(op string, fn interface{}, in reflect.Type) {
+	assertFunc(op, fn, sig(types(in), m.outTyp), sig(types(in), m.outTyp, errType))
+}
+
+func (m Matcher) build(def reflect.Value) interface{} {
+	if len(m.cases) == 0 {
+		panic("fp: Match expects at least one Case")
+	}
+	typ := reflect.FuncOf([]reflect.Type{anyType}, []reflect.Type{m.outTyp, errType}, false)
+	return reflect.MakeFunc(typ, func(in []reflect.Value) []reflect.Value {
+		/* in[0] is interface{}, unwrap it to the dynamic value */
+		v := in[0].Elem()
+		for _, c := range m.cases {
+			if v.IsValid() && v.Type().AssignableTo(c.Type().In(0)) {
+				return m.result(c.Call([]reflect.Value{v}))
+			}
+		}
+		if def.IsValid() {
+			return m.result(def.Call(in))
+		}
+		return []reflect.Value{reflect.Zero(m.outTyp), reflect.ValueOf(fmt.Errorf("%w: %T", ErrNoMatch, in[0].Interface()))}
+	}).Interface()
+}
+
+func (m Matcher) result(out []reflect.Value) []reflect.Value {
+	if len(out) == 1 {
+		return []reflect.Value{convertTo(out[0], m.outTyp), reflect.Zero(errType)}
+	}
+	return []reflect.Value{convertTo(out[0], m.outTyp), out[1]}
+}
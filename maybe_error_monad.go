@@ -1,13 +1,15 @@
 package fp
 
 import (
+	gocontext "context"
+	"fmt"
 	"reflect"
 	"sync"
 )
 
 type Monad interface {
-	// Map func(type1) (type2,&optional error/bool)
-	Map(fn interface{}) Monad
+	// Map func(type1) (type2,&optional error/bool), Retry/Timeout options are supported
+	Map(fn interface{}, opts ...MapOption) Monad
 	// ExpectPass func(type1) (bool)
 	ExpectPass(fn interface{}) Monad
 	// ExpectNoError func(type1) (error)
@@ -18,6 +20,20 @@ type Monad interface {
 	Zip(interface{}, ...Monad) Monad
 	// Once monad
 	Once() Monad
+	// Recover func(error) (type1,error), replace error by a value or another error
+	Recover(fn interface{}) Monad
+	// OrElse use other monad if monad failed or is empty
+	OrElse(other Monad) Monad
+	// MapErr transform error
+	MapErr(fn func(error) error) Monad
+	// Fold onValue func(type1) type2, onError func(error) type2, onEmpty func() type2
+	Fold(onValue, onError, onEmpty interface{}) Value
+	// Tap func(type1), run fn on value without changing monad
+	Tap(fn interface{}) Monad
+	// Async start evaluating monad in background
+	Async() Monad
+	// Await value of monad, fail with ctx error if ctx is done first
+	Await(ctx gocontext.Context) Value
 	// Value of monad
 	Val() Value
 	// fnContainer return error_boolean_monad real container
@@ -60,8 +76,9 @@ func newErrorMonad(fn reflect.Value) errorMonad {
 	return errorMonad{fn: fn}
 }
 
-func (em errorMonad) Map(fn interface{}) Monad {
-	fnVal := toErrMonadFunc(fn)
+func (em errorMonad) Map(fn interface{}, opts ...MapOption) Monad {
+	assertMapper("Monad.Map", fn, em.fn.Type().Out(0))
+	fnVal := toErrMonadFunc(newMapOption(opts).guard("Monad.Map", reflect.ValueOf(fn)).Interface())
 	outTyp := reflect.FuncOf(nil, outTypes(fnVal.Type()), false)
 	return newErrorMonad(reflect.MakeFunc(outTyp, func(in []reflect.Value) []reflect.Value {
 		out := em.fn.Call(in)
@@ -89,6 +106,13 @@ func (em errorMonad) fnContainer() func() (interface{}, bool, error) {
 }
 
 func (em errorMonad) Zip(fn interface{}, others ...Monad) Monad {
+	assertMapper("Monad.Zip", fn, append([]reflect.Type{em.fn.Type().Out(0)}, make([]reflect.Type, len(others))...)...)
+	for _, m := range others {
+		if _, ok := m.(asyncMonad); ok {
+			/* wait all of them concurrently like zip of async monad */
+			return zipAsync(fn, append([]Monad{em}, others...))
+		}
+	}
 	fnVal := toErrMonadFunc(fn)
 	outTyp := reflect.FuncOf(nil, outTypes(fnVal.Type()), false)
 	return newErrorMonad(reflect.MakeFunc(outTyp, func(in []reflect.Value) []reflect.Value {
@@ -128,6 +152,7 @@ func (em errorMonad) ExpectNoError(fn interface{}) Monad {
 }
 
 func (em errorMonad) Expect(fn interface{}) Monad {
+	assertFunc("Monad.Expect", fn, sig(types(em.fn.Type().Out(0)), boolType), sig(types(em.fn.Type().Out(0)), errType))
 	typ := reflect.TypeOf(fn)
 	wrapTyp := reflect.FuncOf(nil, outTypes(em.fn.Type()), false)
 	if typ.NumOut() == 1 && typ.Out(0).AssignableTo(errType) {
@@ -159,7 +184,83 @@ func (em errorMonad) Expect(fn interface{}) Monad {
 	}
 }
 
+func (em errorMonad) Recover(fn interface{}) Monad {
+	typ := em.fn.Type().Out(0)
+	assertFunc("Monad.Recover", fn, sig(types(errType), typ, errType))
+	return newErrorMonad(reflect.MakeFunc(em.fn.Type(), func(in []reflect.Value) []reflect.Value {
+		out := em.fn.Call(in)
+		if e := out[2].Interface(); e != nil && e.(error) != nil {
+			return recoverWith(reflect.ValueOf(fn), typ, out[2])
+		}
+		return out
+	}))
+}
+
+/* recoverWith call Recover callback fnVal with err, error returned by fnVal replaces err and the monad stays failed */
+func recoverWith(fnVal reflect.Value, typ reflect.Type, err reflect.Value) []reflect.Value {
+	out := fnVal.Call([]reflect.Value{err})
+	if e := out[1].Interface(); !isNilObject(e) {
+		return []reflect.Value{reflect.Zero(typ), reflect.ValueOf(false), reflect.ValueOf(e)}
+	}
+	return []reflect.Value{convertTo(out[0], typ), reflect.ValueOf(true), reflect.Zero(errType)}
+}
+
+func (em errorMonad) OrElse(other Monad) Monad {
+	typ := em.fn.Type().Out(0)
+	if om, ok := other.(errorMonad); ok && !om.fn.Type().Out(0).AssignableTo(typ) {
+		panic(fmt.Sprintf("fp: Monad.OrElse expects monad of %v, got %v", typ, om.fn.Type().Out(0)))
+	}
+	return newErrorMonad(reflect.MakeFunc(em.fn.Type(), func(in []reflect.Value) []reflect.Value {
+		out := em.fn.Call(in)
+		if e := out[2].Interface(); (e == nil || e.(error) == nil) && out[1].Bool() {
+			return out
+		}
+		v, ok, err := other.fnContainer()()
+		if err != nil || !ok {
+			return []reflect.Value{reflect.Zero(typ), reflect.ValueOf(ok), errValue(err)}
+		}
+		return []reflect.Value{convertTo(reflect.ValueOf(v), typ), reflect.ValueOf(true), reflect.Zero(errType)}
+	}))
+}
+
+func (em errorMonad) MapErr(fn func(error) error) Monad {
+	return newErrorMonad(reflect.MakeFunc(em.fn.Type(), func(in []reflect.Value) []reflect.Value {
+		out := em.fn.Call(in)
+		if e := out[2].Interface(); e != nil && e.(error) != nil {
+			out[2] = errValue(fn(e.(error)))
+		}
+		return out
+	}))
+}
+
+func (em errorMonad) Fold(onValue, onError, onEmpty interface{}) Value {
+	assertKeyFunc("Monad.Fold", onValue, em.fn.Type().Out(0))
+	rtyp := reflect.TypeOf(onValue).Out(0)
+	assertFunc("Monad.Fold", onError, sig(types(errType), rtyp))
+	assertFunc("Monad.Fold", onEmpty, sig(nil, rtyp))
+	out := em.fn.Call(nil)
+	if e := out[2].Interface(); e != nil && e.(error) != nil {
+		return foldValue(rtyp, reflect.ValueOf(onError).Call(out[2:]))
+	}
+	if !out[1].Bool() {
+		return foldValue(rtyp, reflect.ValueOf(onEmpty).Call(nil))
+	}
+	return foldValue(rtyp, reflect.ValueOf(onValue).Call(out[:1]))
+}
+
+func (em errorMonad) Tap(fn interface{}) Monad {
+	assertFunc("Monad.Tap", fn, sig(types(em.fn.Type().Out(0))))
+	return newErrorMonad(reflect.MakeFunc(em.fn.Type(), func(in []reflect.Value) []reflect.Value {
+		out := em.fn.Call(in)
+		if e := out[2].Interface(); (e == nil || e.(error) == nil) && out[1].Bool() {
+			reflect.ValueOf(fn).Call(out[:1])
+		}
+		return out
+	}))
+}
+
 func (em errorMonad) StreamOf(fn interface{}) Stream {
+	assertMapper("Monad.StreamOf", fn, em.fn.Type().Out(0))
 	fnVal := toErrMonadFunc(fn)
 	ctx := newCtx(nil)
 	evalM := func() (reflect.Value, bool, error) {
@@ -186,7 +287,7 @@ func (em errorMonad) StreamOf(fn interface{}) Stream {
 		ctx.SetErr(err)
 		return newStream(ctx, sc.ElemType(), sc.Next)
 	} else if !ok {
-		return newNilStream()
+		return nilStreamOf(ctx)
 	} else {
 		return out.Interface().(Stream)
 	}
@@ -239,6 +340,30 @@ func toErrMonadFunc(fn interface{}) reflect.Value {
 	})
 }
 
+/* convertTo make v assignable to result of typ, v of interface type is unwrapped */
+func convertTo(v reflect.Value, typ reflect.Type) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Zero(typ)
+	}
+	if v.Type() == typ {
+		return v
+	}
+	out := reflect.New(typ).Elem()
+	out.Set(v)
+	return out
+}
+
+func errValue(err error) reflect.Value {
+	if err == nil {
+		return reflect.Zero(errType)
+	}
+	return reflect.ValueOf(err)
+}
+
+func foldValue(typ reflect.Type, out []reflect.Value) Value {
+	return Value{typ: typ, val: convertTo(out[0], typ)}
+}
+
 func isNilObject(v interface{}) bool {
 	if v == nil {
 		return true
@@ -0,0 +1,177 @@
+package fp
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Merge interleave elements of streams in arrival order, every stream is pulled by its own goroutine
+// the merged stream ends when all streams are exhausted, it fails with the first error of them and the others are closed.
+// Closing merged stream stops a stream made from a channel right away even if the channel is idle,
+// other streams are stopped once their pending element arrives
+func Merge(streams ...Stream) Stream {
+	var its []*streamIterator
+	var typ reflect.Type
+	ctx := newCtx(nil)
+	for _, s := range streams {
+		if isNilStream(s) {
+			/* nil streams contribute nothing but are finished with merged stream */
+			if s != nil {
+				closeWith(ctx, s)
+			}
+			continue
+		}
+		it := s.Iterator().(*streamIterator)
+		if typ == nil {
+			typ = it.q.expectElemTyp
+		} else if it.q.expectElemTyp != typ {
+			panic(fmt.Sprintf("fp: Merge expects streams of %v, got %v", typ, it.q.expectElemTyp))
+		}
+		its = append(its, it)
+	}
+	if len(its) == 0 {
+		return nilStreamOf(ctx)
+	}
+	m := &merger{its: its, out: make(chan reflect.Value), quit: make(chan struct{})}
+	for _, it := range its {
+		if cs, ok := it.q.src.(*channelSource); ok {
+			it.next = m.recv(it, cs.ch)
+		}
+	}
+	ctx.hooks().addCloser(closeFunc(m.stop))
+	return newStream(ctx, typ, func() (reflect.Value, bool) {
+		m.startOnce.Do(m.start)
+		select {
+		case <-m.quit:
+		default:
+			select {
+			case val, ok := <-m.out:
+				if ok {
+					return val, true
+				}
+			case <-m.quit:
+			}
+		}
+		if err := m.error(); err != nil {
+			ctx.SetErr(err)
+		}
+		return reflect.Value{}, false
+	})
+}
+
+type merger struct {
+	its       []*streamIterator
+	out       chan reflect.Value
+	quit      chan struct{}
+	startOnce sync.Once
+	quitOnce  sync.Once
+	mu        sync.Mutex
+	err       error
+}
+
+func (m *merger) start() {
+	var wg sync.WaitGroup
+	for _, it := range m.its {
+		wg.Add(1)
+		go func(it *streamIterator) {
+			defer wg.Done()
+			for it.Next() {
+				select {
+				case m.out <- it.val:
+				case <-m.quit:
+					it.Close()
+					return
+				}
+			}
+			if err := it.Err(); err != nil {
+				m.fail(err)
+			}
+		}(it)
+	}
+	go func() {
+		wg.Wait()
+		close(m.out)
+	}()
+}
+
+/* recv receive ch or quit in one select, so idle channel doesn't keep worker of it once merged stream is stopped */
+func (m *merger) recv(it *streamIterator, ch reflect.Value) iterator {
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(m.quit)},
+	}
+	return func() (reflect.Value, bool) {
+		i, recv, ok := reflect.Select(cases)
+		if i == 0 && ok {
+			return recv, true
+		}
+		if i == 1 {
+			/* abandoned rather than exhausted */
+			it.Close()
+		}
+		return reflect.Value{}, false
+	}
+}
+
+/* fail keep the first error and stop the others */
+func (m *merger) fail(err error) {
+	m.mu.Lock()
+	if m.err == nil {
+		m.err = err
+	}
+	m.mu.Unlock()
+	m.stop()
+}
+
+func (m *merger) error() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+/* stop is called when merged stream is closed, streams are closed directly if they are never pulled */
+func (m *merger) stop() {
+	m.startOnce.Do(func() {
+		for _, it := range m.its {
+			it.Close()
+		}
+	})
+	m.quitOnce.Do(func() { close(m.quit) })
+}
+
+// MergeChannels interleave elements of channels in arrival order by one multi-case select
+// the merged stream ends when all channels are closed, nil channels are ignored
+func MergeChannels(chs ...interface{}) Stream {
+	var cases []reflect.SelectCase
+	var typ reflect.Type
+	for _, ch := range chs {
+		val := reflect.ValueOf(ch)
+		if val.Kind() != reflect.Chan || val.Type().ChanDir()&reflect.RecvDir == 0 {
+			panic(fmt.Sprintf("fp: MergeChannels expects receivable channels, got %T", ch))
+		}
+		if typ == nil {
+			typ = val.Type().Elem()
+		} else if val.Type().Elem() != typ {
+			panic(fmt.Sprintf("fp: MergeChannels expects channels of %v, got %T", typ, ch))
+		}
+		if !val.IsNil() {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: val})
+		}
+	}
+	ctx := newCtx(nil)
+	if typ == nil {
+		return nilStreamOf(ctx)
+	}
+	return newStream(ctx, typ, func() (reflect.Value, bool) {
+		for len(cases) > 0 {
+			i, recv, ok := reflect.Select(cases)
+			if ok {
+				return recv, true
+			}
+			/* closed channel */
+			cases = append(cases[:i], cases[i+1:]...)
+		}
+		return reflect.Value{}, false
+	})
+}
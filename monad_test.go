@@ -1,10 +1,13 @@
 package fp
 
 import (
+	gocontext "context"
 	"errors"
+	"fmt"
 	"net"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -506,3 +509,313 @@ func (suite *MonadTestSuite) TestBoolVal() {
 func (suite *MonadTestSuite) TestNilVal() {
 	suite.Error(newNilMonad(errors.New(`error`)).Val().Err())
 }
+
+func (suite *MonadTestSuite) TestRecover() {
+	fail := errors.New("fail")
+	m := M("a").Map(strconv.Atoi).Recover(func(err error) (int, error) { return -1, nil })
+	suite.Equal(-1, m.Val().Int())
+	suite.NoError(m.Val().Err())
+
+	m = M("a").Map(strconv.Atoi).Recover(func(err error) (int, error) { return 0, fail })
+	suite.Equal(fail, m.Val().Err())
+
+	suite.Equal(12, M("12").Map(strconv.Atoi).Recover(func(err error) (int, error) { return -1, nil }).Val().Int())
+	suite.Equal(-1, newNilMonad(fail).Recover(func(err error) (int, error) { return -1, nil }).Val().Int())
+	suite.Zero(newNilMonad(nil).Recover(func(err error) (int, error) { return -1, nil }).Val().Int())
+
+	suite.Panics(func() {
+		M(1).Recover(func(err error) (string, error) { return "", nil })
+	})
+}
+
+func (suite *MonadTestSuite) TestRecoverPropagateError() {
+	fail := errors.New("fail")
+	var mapped bool
+	v := M("a").Map(strconv.Atoi).Recover(func(err error) (int, error) { return 1, fail }).Map(func(i int) int {
+		mapped = true
+		return i
+	}).Val()
+	suite.Equal(fail, v.Err())
+	suite.False(mapped)
+
+	v = newNilMonad(errors.New("origin")).Recover(func(err error) (int, error) { return 1, fail }).Val()
+	suite.Equal(fail, v.Err())
+
+	var typedNil *PanicError
+	v = M("a").Map(strconv.Atoi).Recover(func(err error) (int, *PanicError) { return 1, typedNil }).Val()
+	suite.NoError(v.Err())
+	suite.Equal(1, v.Int())
+}
+
+func (suite *MonadTestSuite) TestNilMonadRecoverIsLazy() {
+	var calls int
+	m := newNilMonad(errors.New("fail")).Recover(func(err error) (int, error) {
+		calls++
+		return -1, nil
+	})
+	suite.Zero(calls)
+	suite.Equal(-1, m.Val().Int())
+	suite.Equal(1, calls)
+}
+
+func (suite *MonadTestSuite) TestOrElse() {
+	suite.Equal(2, M("a").Map(strconv.Atoi).OrElse(M(2)).Val().Int())
+	suite.Equal(1, M("1").Map(strconv.Atoi).OrElse(M(2)).Val().Int())
+	suite.Equal(2, M(1, false).OrElse(M(2)).Val().Int())
+	suite.Equal(2, newNilMonad(nil).OrElse(M(2)).Val().Int())
+	suite.Error(M("a").Map(strconv.Atoi).OrElse(M("b").Map(strconv.Atoi)).Val().Err())
+	suite.Panics(func() {
+		M(1).OrElse(M("a"))
+	})
+}
+
+func (suite *MonadTestSuite) TestMapErr() {
+	fail := errors.New("fail")
+	wrap := func(err error) error { return fmt.Errorf("wrap: %w", err) }
+	err := M("a").Map(strconv.Atoi).MapErr(wrap).Val().Err()
+	suite.Contains(err.Error(), "wrap:")
+	suite.True(errors.Is(newNilMonad(fail).MapErr(wrap).Val().Err(), fail))
+	suite.Equal(1, M(1).MapErr(wrap).Val().Int())
+}
+
+func (suite *MonadTestSuite) TestFold() {
+	fold := func(m Monad) string {
+		return m.Fold(
+			func(i int) string { return "value:" + strconv.Itoa(i) },
+			func(err error) string { return "error" },
+			func() string { return "empty" },
+		).String()
+	}
+	suite.Equal("value:1", fold(M("1").Map(strconv.Atoi)))
+	suite.Equal("error", fold(M("a").Map(strconv.Atoi)))
+	suite.Equal("empty", fold(M(1, false)))
+	suite.Equal("error", fold(newNilMonad(errors.New("fail"))))
+	suite.Equal("empty", fold(newNilMonad(nil)))
+	suite.Panics(func() {
+		M(1).Fold(func(i int) string { return "" }, func(err error) int { return 0 }, func() string { return "" })
+	})
+}
+
+func (suite *MonadTestSuite) TestTap() {
+	var seen []int
+	m := M("1").Map(strconv.Atoi).Tap(func(i int) { seen = append(seen, i) })
+	suite.Empty(seen)
+	suite.Equal(1, m.Val().Int())
+	suite.Equal([]int{1}, seen)
+
+	M("a").Map(strconv.Atoi).Tap(func(i int) { seen = append(seen, i) }).Val()
+	newNilMonad(nil).Tap(func(i int) { seen = append(seen, i) }).Val()
+	suite.Equal([]int{1}, seen)
+}
+
+func (suite *MonadTestSuite) TestOrDefault() {
+	suite.Equal(1, M("1").Map(strconv.Atoi).Val().OrDefault(-1))
+	suite.Equal(-1, M("a").Map(strconv.Atoi).Val().OrDefault(-1))
+	suite.Equal(-1, M(1, false).Val().OrDefault(-1))
+	suite.Equal(-1, newNilMonad(nil).Val().OrDefault(-1))
+}
+
+func (suite *MonadTestSuite) TestAsync() {
+	m := Async(func() (int, error) { return strconv.Atoi("12") })
+	suite.Equal(12, m.Val().Int())
+	suite.Equal(12, m.Await(gocontext.Background()).Int())
+	suite.Equal(13, m.Map(func(i int) int { return i + 1 }).Val().Int())
+	suite.Equal([]int{0, 1}, m.StreamOf(func(i int) []int { return Times(2).Ints() }).Ints())
+
+	suite.Error(Async(func() (int, error) { return strconv.Atoi("a") }).Val().Err())
+	suite.Equal(3, M("3").Map(strconv.Atoi).Async().Val().Int())
+	suite.Error(newNilMonad(errors.New("fail")).Async().Val().Err())
+
+	var pe *PanicError
+	suite.True(errors.As(Async(func() int { panic("boom") }).Val().Err(), &pe))
+
+	suite.Panics(func() { Async(func(int) int { return 0 }) })
+}
+
+func (suite *MonadTestSuite) TestAwaitTimeout() {
+	release := make(chan struct{})
+	defer close(release)
+	m := Async(func() int { <-release; return 1 })
+	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 10*time.Millisecond)
+	defer cancel()
+	suite.Equal(gocontext.DeadlineExceeded, m.Await(ctx).Err())
+	suite.Equal(1, M(1).Await(gocontext.Background()).Int())
+}
+
+func (suite *MonadTestSuite) TestAsyncZipConcurrent() {
+	/* each side waits until the other has started, sequential zip would fail */
+	aStarted, bStarted := make(chan struct{}), make(chan struct{})
+	wait := func(started, other chan struct{}, v int) func() (int, error) {
+		return func() (int, error) {
+			close(started)
+			select {
+			case <-other:
+				return v, nil
+			case <-time.After(time.Second):
+				return 0, errors.New("not concurrent")
+			}
+		}
+	}
+	sum := func(a, b, c int) int { return a + b + c }
+	lazy := func(fn func() (int, error)) Monad { return M(0).Map(func(int) (int, error) { return fn() }) }
+	v := Async(func() int { return 1 }).Zip(sum, lazy(wait(aStarted, bStarted, 2)), lazy(wait(bStarted, aStarted, 3))).Val()
+	suite.NoError(v.Err())
+	suite.Equal(6, v.Int())
+
+	/* sync receiver zips async monads concurrently too */
+	aStarted, bStarted = make(chan struct{}), make(chan struct{})
+	v = M(1).Zip(sum, Async(wait(aStarted, bStarted, 2)), Async(wait(bStarted, aStarted, 3))).Val()
+	suite.NoError(v.Err())
+	suite.Equal(6, v.Int())
+}
+
+func (suite *MonadTestSuite) TestAsyncZipFailFast() {
+	fail := errors.New("fail")
+	canceled := make(chan error, 1)
+	slow := Async(func(ctx gocontext.Context) (int, error) {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return 0, ctx.Err()
+	})
+	failing := Async(func() (int, error) { return 0, fail })
+	err := failing.Zip(func(a, b int) int { return a + b }, slow).Val().Err()
+	suite.Equal(fail, err)
+	suite.Equal(gocontext.Canceled, <-canceled)
+
+	/* sync receiver fails fast as well */
+	canceled = make(chan error, 1)
+	slow = Async(func(ctx gocontext.Context) (int, error) {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return 0, ctx.Err()
+	})
+	err = M(1).Zip(func(a, b, c int) int { return a + b + c }, slow, Async(func() (int, error) { return 0, fail })).Val().Err()
+	suite.Equal(fail, err)
+	suite.Equal(gocontext.Canceled, <-canceled)
+
+	empty := M(1, false)
+	suite.NoError(Async(func() int { return 1 }).Zip(func(a, b int) int { return a + b }, empty).Val().Err())
+	suite.Zero(Async(func() int { return 1 }).Zip(func(a, b int) int { return a + b }, empty).Val().Int())
+}
+
+func (suite *MonadTestSuite) TestAsyncZipCancelScoped() {
+	fail := errors.New("fail")
+	release := make(chan struct{})
+	shared := Async(func(ctx gocontext.Context) (int, error) {
+		select {
+		case <-release:
+			return 1, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	})
+	sum := func(a, b int) int { return a + b }
+	ok := Async(func() int { return 1 }).Zip(sum, shared)
+	failed := Async(func() (int, error) { return 0, fail }).Zip(sum, shared)
+	suite.Equal(fail, failed.Val().Err())
+	/* shared is still waited by ok, so failed zip does not cancel it */
+	close(release)
+	v := ok.Val()
+	suite.NoError(v.Err())
+	suite.Equal(2, v.Int())
+}
+
+func (suite *MonadTestSuite) TestMapRetry() {
+	var calls int
+	clock := &fakeClock{fire: true}
+	v := M("1").Map(func(s string) (int, error) {
+		calls++
+		if calls < 2 {
+			return 0, errors.New("transient")
+		}
+		return strconv.Atoi(s)
+	}, Retry(2, ConstantBackoff(time.Second)), WithClock(clock)).Val()
+	suite.NoError(v.Err())
+	suite.Equal(1, v.Int())
+	suite.Equal(2, calls)
+	suite.Equal([]time.Duration{time.Second}, clock.waits)
+
+	block := make(chan struct{})
+	defer close(block)
+	err := M("1").Map(func(s string) (int, error) {
+		<-block
+		return 0, nil
+	}, Timeout(time.Second), WithClock(&fakeClock{fire: true})).Val().Err()
+	suite.Equal(ErrTimeout, err)
+}
+
+func (suite *MonadTestSuite) TestSequence() {
+	parse := func(s string) Monad { return M(s).Map(strconv.Atoi) }
+	ms := []Monad{parse("1"), parse("2"), parse("3")}
+	suite.Equal([]int{1, 2, 3}, Sequence(StreamOf(ms)).Val().Ints())
+
+	ms = []Monad{parse("1"), parse("a"), parse("3")}
+	suite.Error(Sequence(StreamOf(ms)).Val().Err())
+
+	ms = []Monad{parse("1"), M(2, false)}
+	v := Sequence(StreamOf(ms)).Val()
+	suite.NoError(v.Err())
+	suite.Nil(v.Result())
+
+	ms = []Monad{M(1), M("a")}
+	suite.Equal([]interface{}{1, "a"}, Sequence(StreamOf(ms)).Val().Result())
+
+	err := errors.New("fail")
+	suite.Equal(err, Sequence(StreamOf(ms).Map(func(m Monad) (Monad, error) { return m, err })).Val().Err())
+	suite.Panics(func() { Sequence(StreamOf([]int{1})) })
+}
+
+func (suite *MonadTestSuite) TestSequenceLazy() {
+	var pulled int
+	s := StreamOf([]string{"1", "2"}).Foreach(func(string) { pulled++ }).Map(func(s string) Monad { return M(s).Map(strconv.Atoi) })
+	m := Sequence(s)
+	suite.Zero(pulled)
+	suite.Equal([]int{1, 2}, m.Val().Ints())
+	suite.Equal([]int{1, 2}, m.Val().Ints())
+	suite.Equal(2, pulled)
+
+	err := errors.New("fail")
+	m = Sequence(StreamOf([]int{1}).Map(func(int) (Monad, error) { return nil, err }))
+	suite.Equal(err, m.Val().Err())
+
+	/* stream of slice keeps static slice type */
+	var sum int
+	Sequence(StreamOf([]Monad{M(1), M(2)})).Map(func(xs []int) int { return xs[0] + xs[1] }).Val().To(&sum)
+	suite.Equal(3, sum)
+}
+
+func (suite *MonadTestSuite) TestTraverse() {
+	var calls int
+	m := Traverse(StreamOf([]string{"1", "2"}), func(s string) (int, error) {
+		calls++
+		return strconv.Atoi(s)
+	})
+	suite.Zero(calls)
+	suite.Equal([]int{1, 2}, m.Val().Ints())
+	suite.Equal([]int{1, 2}, m.Val().Ints())
+	suite.Equal(2, calls)
+
+	var closed bool
+	calls = 0
+	m = Traverse(StreamOf([]string{"1", "a", "3"}).Finally(func() { closed = true }), func(s string) (int, error) {
+		calls++
+		return strconv.Atoi(s)
+	})
+	suite.Error(m.Val().Err())
+	suite.Equal(2, calls)
+	suite.True(closed)
+
+	m = Traverse(StreamOf([]string{"1", "a"}).Map(strconv.Atoi), func(i int) int { return i })
+	suite.Error(m.Val().Err())
+
+	suite.Equal([]int{}, Traverse(StreamOf([]int{}), func(i int) int { return i }).Val().Ints())
+}
+
+func (suite *MonadTestSuite) TestFirstM() {
+	suite.Equal(2, StreamOf([]int{1, 2}).Skip(1).FirstM().Map(func(i int) int { return i }).Val().Int())
+	v := StreamOf([]int{}).FirstM().Val()
+	suite.NoError(v.Err())
+	suite.Nil(v.Result())
+	suite.Error(StreamOf([]string{"a"}).Map(strconv.Atoi).FirstM().Val().Err())
+	suite.Nil(newNilStream().FirstM().Val().Result())
+}
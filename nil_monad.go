@@ -1,9 +1,11 @@
 package fp
 
+import "reflect"
+
 type nilMonad struct{ err error }
 
-func newNilMonad(err error) nilMonad        { return nilMonad{err: err} }
-func (m nilMonad) Map(fn interface{}) Monad { return m }
+func newNilMonad(err error) nilMonad                           { return nilMonad{err: err} }
+func (m nilMonad) Map(fn interface{}, opts ...MapOption) Monad { return m }
 
 func (m nilMonad) ExpectPass(fn interface{}) Monad { return m }
 
@@ -17,6 +19,40 @@ func (m nilMonad) Once() Monad { return m }
 
 func (m nilMonad) Val() Value { return Value{err: m.err} }
 
+func (m nilMonad) Recover(fn interface{}) Monad {
+	assertFunc("Monad.Recover", fn, sig(types(errType), nil, errType))
+	if m.err == nil {
+		return m
+	}
+	fnVal := reflect.ValueOf(fn)
+	typ := fnVal.Type().Out(0)
+	return newErrorMonad(reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{typ, boolType, errType}, false), func([]reflect.Value) []reflect.Value {
+		return recoverWith(fnVal, typ, reflect.ValueOf(m.err))
+	}))
+}
+
+func (m nilMonad) OrElse(other Monad) Monad { return other }
+
+func (m nilMonad) MapErr(fn func(error) error) Monad {
+	if m.err == nil {
+		return m
+	}
+	return newNilMonad(fn(m.err))
+}
+
+func (m nilMonad) Fold(onValue, onError, onEmpty interface{}) Value {
+	assertKeyFunc("Monad.Fold", onValue, nil)
+	rtyp := reflect.TypeOf(onValue).Out(0)
+	assertFunc("Monad.Fold", onError, sig(types(errType), rtyp))
+	assertFunc("Monad.Fold", onEmpty, sig(nil, rtyp))
+	if m.err != nil {
+		return foldValue(rtyp, reflect.ValueOf(onError).Call([]reflect.Value{reflect.ValueOf(m.err)}))
+	}
+	return foldValue(rtyp, reflect.ValueOf(onEmpty).Call(nil))
+}
+
+func (m nilMonad) Tap(fn interface{}) Monad { return m }
+
 func (m nilMonad) fnContainer() func() (interface{}, bool, error) {
 	return func() (interface{}, bool, error) { return nil, false, m.err }
 }
@@ -2,17 +2,30 @@ package fp
 
 import "reflect"
 
-type nilStream struct{}
+/* nilStream is empty, hooks are recorded and run by terminal ops like other streams */
+type nilStream struct{ lc *lifecycle }
 
-func newNilStream() Stream { return &nilStream{} }
+func newNilStream() Stream { return &nilStream{lc: &lifecycle{}} }
 
-func (ns *nilStream) Map(fn interface{}) Stream     { return ns }
-func (ns *nilStream) FlatMap(fn interface{}) Stream { return ns }
-func (ns *nilStream) Filter(fn interface{}) Stream  { return ns }
-func (ns *nilStream) Reject(fn interface{}) Stream  { return ns }
-func (ns *nilStream) Foreach(fn interface{}) Stream { return ns }
-func (ns *nilStream) Flatten() Stream               { return ns }
+/* nilStreamOf make nil stream finishing hooks of ctx, for operators which find out result is empty before building it */
+func nilStreamOf(ctx context) Stream { return &nilStream{lc: ctx.hooks()} }
+
+/* done finish ns as exhausted */
+func (ns *nilStream) done() { ns.lc.finish(nil, true) }
+
+/* ctx of non nil stream derived from ns, they share hooks */
+func (ns *nilStream) ctx() context { return newCtx(&_context{lc: ns.lc}) }
+
+func (ns *nilStream) Map(fn interface{}, opts ...MapOption) Stream             { return ns }
+func (ns *nilStream) MapOrDeadLetter(fn interface{}, dl interface{}) Stream    { return ns }
+func (ns *nilStream) FlatMap(fn interface{}) Stream                            { return ns }
+func (ns *nilStream) Filter(fn interface{}) Stream                             { return ns }
+func (ns *nilStream) FilterOrDeadLetter(fn interface{}, dl interface{}) Stream { return ns }
+func (ns *nilStream) Reject(fn interface{}) Stream                             { return ns }
+func (ns *nilStream) Foreach(fn interface{}) Stream                            { return ns }
+func (ns *nilStream) Flatten() Stream                                          { return ns }
 func (ns *nilStream) Reduce(initval interface{}, fn interface{}) Value {
+	ns.done()
 	return Value{
 		typ: reflect.TypeOf(initval),
 		val: reflect.ValueOf(initval),
@@ -20,6 +33,7 @@ func (ns *nilStream) Reduce(initval interface{}, fn interface{}) Value {
 }
 func (ns *nilStream) Reduce0(fn interface{}) Value {
 	typ := reflect.TypeOf(fn).Out(0)
+	ns.done()
 	return Value{
 		typ: typ,
 		val: reflect.Zero(typ),
@@ -28,10 +42,11 @@ func (ns *nilStream) Reduce0(fn interface{}) Value {
 func (ns *nilStream) Partition(size int) Stream                                { return ns }
 func (ns *nilStream) PartitionBy(fn interface{}, includeSplittor bool) Stream  { return ns }
 func (ns *nilStream) LPartitionBy(fn interface{}, includeSplittor bool) Stream { return ns }
-func (ns *nilStream) First() Value                                             { return Value{} }
-func (ns *nilStream) IsEmpty() bool                                            { return true }
-func (ns *nilStream) HasSomething() bool                                       { return false }
-func (ns *nilStream) Exists() bool                                             { return false }
+func (ns *nilStream) First() Value                                             { ns.done(); return Value{} }
+func (ns *nilStream) FirstM() Monad                                            { ns.done(); return newNilMonad(nil) }
+func (ns *nilStream) IsEmpty() bool                                            { ns.done(); return true }
+func (ns *nilStream) HasSomething() bool                                       { ns.done(); return false }
+func (ns *nilStream) Exists() bool                                             { ns.done(); return false }
 func (ns *nilStream) Take(n int) Stream                                        { return ns }
 func (ns *nilStream) TakeWhile(fn interface{}) Stream                          { return ns }
 func (ns *nilStream) Skip(size int) Stream                                     { return ns }
@@ -40,20 +55,67 @@ func (ns *nilStream) Sort() Stream                                             {
 func (ns *nilStream) SortBy(fn interface{}) Stream                             { return ns }
 func (ns *nilStream) Uniq() Stream                                             { return ns }
 func (ns *nilStream) UniqBy(fn interface{}) Stream                             { return ns }
-func (ns *nilStream) Size() int                                                { return 0 }
-func (ns *nilStream) Count() int                                               { return 0 }
-func (ns *nilStream) Contains(interface{}) bool                                { return false }
-func (ns *nilStream) ContainsBy(fn interface{}) bool                           { return false }
+func (ns *nilStream) Size() int                                                { ns.done(); return 0 }
+func (ns *nilStream) Count() int                                               { ns.done(); return 0 }
+func (ns *nilStream) Contains(interface{}) bool                                { ns.done(); return false }
+func (ns *nilStream) ContainsBy(fn interface{}) bool                           { ns.done(); return false }
 func (ns *nilStream) ToSource() Source                                         { return newNilSource() }
+func (ns *nilStream) Iterator() Iterator                                       { return newStream(ns.ctx(), nil, nil).Iterator() }
 func (ns *nilStream) Sub(other Stream) Stream                                  { return ns }
 func (ns *nilStream) SubBy(other Stream, keyfn interface{}) Stream             { return ns }
 func (ns *nilStream) Interact(other Stream) Stream                             { return ns }
 func (ns *nilStream) InteractBy(other Stream, keyfn interface{}) Stream        { return ns }
-func (ns *nilStream) Union(o Stream) Stream                                    { return o }
-func (ns *nilStream) ToSet() KVStream                                          { return newNilKVStream() }
-func (ns *nilStream) ToSetBy(fn interface{}) KVStream                          { return newNilKVStream() }
-func (ns *nilStream) GroupBy(fn interface{}) KVStream                          { return newNilKVStream() }
-func (ns *nilStream) Reverse() Stream                                          { return ns }
+func (ns *nilStream) Union(o Stream) Stream {
+	if o == nil {
+		return ns
+	}
+	if isNilStream(o) {
+		closeWith(ns.ctx(), o)
+		return ns
+	}
+	/* o is closed with the union like q.Union does, its hooks are left untouched */
+	src := o.ToSource()
+	ctx := ns.ctx()
+	closeWith(ctx, o)
+	return newStream(ctx, src.ElemType(), src.Next)
+}
+func (ns *nilStream) ToSet() KVStream                 { return &nilkvStream{lc: ns.lc} }
+func (ns *nilStream) ToSetBy(fn interface{}) KVStream { return &nilkvStream{lc: ns.lc} }
+func (ns *nilStream) GroupBy(fn interface{}) KVStream { return &nilkvStream{lc: ns.lc} }
+func (ns *nilStream) Reverse() Stream                 { return ns }
+func (ns *nilStream) Cache() Stream                   { return ns }
+func (ns *nilStream) CacheLimit(size int) Stream      { return ns }
+func (ns *nilStream) SafeMode() Stream                { return ns }
+func (ns *nilStream) OnComplete(fn func()) Stream {
+	ns.lc.onComplete = append(ns.lc.onComplete, fn)
+	return ns
+}
+func (ns *nilStream) OnError(fn func(error)) Stream {
+	ns.lc.onError = append(ns.lc.onError, fn)
+	return ns
+}
+func (ns *nilStream) Finally(fn func()) Stream {
+	ns.lc.finally = append(ns.lc.finally, fn)
+	return ns
+}
+func (ns *nilStream) Close() error {
+	ns.lc.finish(nil, false)
+	return nil
+}
+
+func (ns *nilStream) Shard(n int, keyFn interface{}) []Stream {
+	if n < 1 {
+		panic("shard number should be greater than 0")
+	}
+	shards := make([]Stream, n)
+	for i := range shards {
+		shards[i] = ns
+	}
+	return shards
+}
+func (ns *nilStream) Distribute(n int, keyFn interface{}, worker StreamProcessor) error {
+	return distribute(ns.Shard(n, keyFn), worker, func() error { return nil })
+}
 func (ns *nilStream) Append(element ...interface{}) Stream {
 	if len(element) == 0 {
 		return ns
@@ -63,14 +125,17 @@ func (ns *nilStream) Append(element ...interface{}) Stream {
 	for i := 0; i < len(element); i++ {
 		slice.Index(i).Set(reflect.ValueOf(element[i]))
 	}
-	return StreamOf(slice.Interface())
+	ctx := ns.ctx()
+	elemTyp, it, src := makeSourceIter(ctx, slice)
+	return newStream(ctx, elemTyp, it).withSource(src)
 }
 func (ns *nilStream) Prepend(element ...interface{}) Stream        { return ns.Append(element...) }
 func (ns *nilStream) Zip(other Stream, fn interface{}) Stream      { return ns }
 func (ns *nilStream) ZipN(fn interface{}, others ...Stream) Stream { return ns }
-func (ns *nilStream) Branch(processors ...StreamProcessor)         {}
-func (ns *nilStream) Run()                                         {}
+func (ns *nilStream) Branch(processors ...StreamProcessor)         { ns.done() }
+func (ns *nilStream) Run()                                         { ns.done() }
 func (ns *nilStream) ToSlice(ptr interface{}) error {
+	ns.done()
 	val := reflect.ValueOf(ptr)
 	if elem := val.Elem(); elem.IsValid() && elem.Len() > 0 {
 		elem.SetLen(0)
@@ -78,33 +143,36 @@ func (ns *nilStream) ToSlice(ptr interface{}) error {
 	return nil
 }
 
-func (ns *nilStream) Strings() []string             { return nil }
-func (ns *nilStream) StringsList() [][]string       { return nil }
-func (ns *nilStream) Ints() []int                   { return nil }
-func (ns *nilStream) Float64s() []float64           { return nil }
-func (ns *nilStream) Bytes() []byte                 { return nil }
-func (ns *nilStream) Int64s() []int64               { return nil }
-func (ns *nilStream) Int32s() []int32               { return nil }
-func (ns *nilStream) Uints() []uint                 { return nil }
-func (ns *nilStream) Uint32s() []uint32             { return nil }
-func (ns *nilStream) Uint64s() []uint64             { return nil }
-func (ns *nilStream) JoinStrings(seq string) string { return "" }
-func (ks *nilStream) Error() error                  { return nil }
+func (ns *nilStream) Strings() []string             { ns.done(); return nil }
+func (ns *nilStream) StringsList() [][]string       { ns.done(); return nil }
+func (ns *nilStream) Ints() []int                   { ns.done(); return nil }
+func (ns *nilStream) Float64s() []float64           { ns.done(); return nil }
+func (ns *nilStream) Bytes() []byte                 { ns.done(); return nil }
+func (ns *nilStream) Int64s() []int64               { ns.done(); return nil }
+func (ns *nilStream) Int32s() []int32               { ns.done(); return nil }
+func (ns *nilStream) Uints() []uint                 { ns.done(); return nil }
+func (ns *nilStream) Uint32s() []uint32             { ns.done(); return nil }
+func (ns *nilStream) Uint64s() []uint64             { ns.done(); return nil }
+func (ns *nilStream) JoinStrings(seq string) string { ns.done(); return "" }
+func (ks *nilStream) Error() error                  { ks.done(); return nil }
 
-type nilkvStream struct{}
+type nilkvStream struct{ lc *lifecycle }
 
-func newNilKVStream() KVStream                          { return &nilkvStream{} }
+func newNilKVStream() KVStream                          { return &nilkvStream{lc: &lifecycle{}} }
+func (ks *nilkvStream) done()                           { ks.lc.finish(nil, true) }
 func (ks *nilkvStream) Foreach(fn interface{}) KVStream { return ks }
 func (ks *nilkvStream) Map(fn interface{}) KVStream     { return ks }
-func (ks *nilkvStream) ZipMap(fn interface{}) Stream    { return newNilStream() }
+func (ks *nilkvStream) ZipMap(fn interface{}) Stream    { return &nilStream{lc: ks.lc} }
 func (ks *nilkvStream) Filter(fn interface{}) KVStream  { return ks }
 func (ks *nilkvStream) Reject(fn interface{}) KVStream  { return ks }
-func (ks *nilkvStream) Contains(key interface{}) bool   { return false }
-func (ks *nilkvStream) Keys() Stream                    { return newNilStream() }
-func (ks *nilkvStream) Values() Stream                  { return newNilStream() }
-func (ks *nilkvStream) Size() int                       { return 0 }
-func (ks *nilkvStream) Run()                            {}
+func (ks *nilkvStream) Contains(key interface{}) bool   { ks.done(); return false }
+func (ks *nilkvStream) Keys() Stream                    { return &nilStream{lc: ks.lc} }
+func (ks *nilkvStream) Values() Stream                  { return &nilStream{lc: ks.lc} }
+func (ks *nilkvStream) Size() int                       { ks.done(); return 0 }
+func (ks *nilkvStream) Run()                            { ks.done() }
+func (ks *nilkvStream) SafeMode() KVStream              { return ks }
 func (ks *nilkvStream) To(dstPtr interface{}) error {
+	ks.done()
 	val := reflect.ValueOf(dstPtr)
 	if !val.Elem().IsValid() || val.Elem().IsNil() {
 		val.Elem().Set(reflect.MakeMap(val.Elem().Type()))
@@ -0,0 +1,107 @@
+package fp
+
+import "reflect"
+
+// Ordering compose key functions into less function, key function is func(element_type) any_type or Selector.
+// Keys are compared by kind, time.Time and types with method Compare(T) int or Less(T) bool are supported
+type Ordering struct {
+	keys []orderKey
+}
+
+type orderKey struct {
+	fn   interface{}
+	desc bool
+}
+
+// OrderBy ascending key
+func OrderBy(keyFn interface{}) Ordering {
+	return Ordering{}.ThenBy(keyFn)
+}
+
+// OrderByDesc descending key
+func OrderByDesc(keyFn interface{}) Ordering {
+	return Ordering{}.ThenByDesc(keyFn)
+}
+
+func (o Ordering) ThenBy(keyFn interface{}) Ordering {
+	return o.then(keyFn, false)
+}
+
+func (o Ordering) ThenByDesc(keyFn interface{}) Ordering {
+	return o.then(keyFn, true)
+}
+
+func (o Ordering) then(keyFn interface{}, desc bool) Ordering {
+	if _, ok := keyFn.(Selector); !ok {
+		assertKeyFunc("OrderBy", keyFn, nil)
+	}
+	o.keys = append(append([]orderKey{}, o.keys...), orderKey{fn: keyFn, desc: desc})
+	return o
+}
+
+// Less build func(element_type, element_type) bool, element type is inferred from key functions
+func (o Ordering) Less() interface{} {
+	return o.resolve(o.elemType()).Interface()
+}
+
+// Compare build func(element_type, element_type) int
+func (o Ordering) Compare() interface{} {
+	typ := o.elemType()
+	cmp := o.comparator(typ)
+	return reflect.MakeFunc(reflect.FuncOf([]reflect.Type{typ, typ}, []reflect.Type{intType}, false), func(in []reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.ValueOf(cmp(in[0], in[1]))}
+	}).Interface()
+}
+
+/* elemType infer element type from input of the first typed key function */
+func (o Ordering) elemType() reflect.Type {
+	for _, k := range o.keys {
+		if typ := reflect.TypeOf(k.fn); typ.Kind() == reflect.Func && typ.In(0).Kind() != reflect.Interface {
+			return typ.In(0)
+		}
+	}
+	panic("fp: Ordering can not infer element type from selectors, pass it to SortBy directly")
+}
+
+func (o Ordering) resolve(typ reflect.Type) reflect.Value {
+	cmp := o.comparator(typ)
+	return reflect.MakeFunc(reflect.FuncOf([]reflect.Type{typ, typ}, []reflect.Type{boolType}, false), func(in []reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.ValueOf(cmp(in[0], in[1]) < 0)}
+	})
+}
+
+/* comparator compare elements of typ key by key */
+func (o Ordering) comparator(typ reflect.Type) func(a, b reflect.Value) int {
+	keys := make([]reflect.Value, len(o.keys))
+	for i, k := range o.keys {
+		fn := resolveSelector(k.fn, typ)
+		assertKeyFunc("OrderBy", fn, typ)
+		keys[i] = reflect.ValueOf(fn)
+	}
+	return func(a, b reflect.Value) int {
+		for i, key := range keys {
+			ka, kb := key.Call([]reflect.Value{a})[0], key.Call([]reflect.Value{b})[0]
+			if c := compareValue(key.Type().Out(0), ka, kb); c != 0 {
+				if o.keys[i].desc {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+/* resolveLess turn Selector or Ordering into less function of typ, other fn is returned as is */
+func resolveLess(fn interface{}, typ reflect.Type) interface{} {
+	if typ == nil {
+		return fn
+	}
+	switch o := fn.(type) {
+	case Selector:
+		return OrderBy(o).resolve(typ).Interface()
+	case Ordering:
+		return o.resolve(typ).Interface()
+	}
+	return fn
+}
@@ -0,0 +1,131 @@
+package fp
+
+import (
+	gocontext "context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+type PageOption func(*pageOption)
+
+type pageOption struct {
+	prefetch  bool
+	retry     int
+	backoff   time.Duration
+	retryable func(error) bool
+}
+
+// WithPrefetch fetch next page in background while current page is consumed
+func WithPrefetch() PageOption {
+	return func(opt *pageOption) { opt.prefetch = true }
+}
+
+// WithPageRetry retry failed page fetch at most times, wait backoff between retries
+func WithPageRetry(times int, backoff time.Duration) PageOption {
+	return func(opt *pageOption) {
+		opt.retry = times
+		opt.backoff = backoff
+	}
+}
+
+// WithPageRetryIf only retry page fetch errors satisfying fn, by default errors of context cancellation are not retried
+func WithPageRetryIf(fn func(error) bool) PageOption {
+	return func(opt *pageOption) { opt.retryable = fn }
+}
+
+/* isRetryablePageError is default of WithPageRetryIf, it is useless to retry fetch once its context is done */
+func isRetryablePageError(err error) bool {
+	return !errors.Is(err, gocontext.Canceled) && !errors.Is(err, gocontext.DeadlineExceeded)
+}
+
+// StreamOfPages create stream by paginated api, fetch should be func(token string) ([]element_type, next_token string, error)
+// first page is fetched with empty token, stream ends when next token is empty
+func StreamOfPages(fetch interface{}, opts ...PageOption) Stream {
+	fnVal := reflect.ValueOf(fetch)
+	if !isPageFunction(fnVal) {
+		panic("StreamOfPages function must be func(string) ([]element_type, string, error), now " + fnVal.Type().String())
+	}
+	opt := &pageOption{retryable: isRetryablePageError}
+	for _, fn := range opts {
+		fn(opt)
+	}
+	ctx := newCtx(nil)
+	source := &pageSource{fetch: fnVal, opt: opt}
+	return newStream(ctx, fnVal.Type().Out(0).Elem(), func() (reflect.Value, bool) {
+		val, ok, err := source.Next()
+		if err != nil {
+			ctx.SetErr(err)
+		}
+		return val, ok
+	})
+}
+
+func isPageFunction(fn reflect.Value) bool {
+	typ := fn.Type()
+	return typ.Kind() == reflect.Func && typ.NumIn() == 1 && typ.In(0).Kind() == reflect.String &&
+		typ.NumOut() == 3 && typ.Out(0).Kind() == reflect.Slice && typ.Out(1).Kind() == reflect.String && typ.Out(2) == errType
+}
+
+type page struct {
+	items reflect.Value
+	next  string
+	err   error
+}
+
+type pageSource struct {
+	fetch    reflect.Value
+	opt      *pageOption
+	items    reflect.Value
+	offset   int
+	next     string
+	started  bool
+	done     bool
+	prefetch chan page
+}
+
+func (ps *pageSource) Next() (reflect.Value, bool, error) {
+	for !ps.done {
+		if ps.items.IsValid() && ps.offset < ps.items.Len() {
+			ps.offset++
+			return ps.items.Index(ps.offset - 1), true, nil
+		}
+		if ps.started && ps.next == "" {
+			ps.done = true
+			break
+		}
+		p := ps.nextPage()
+		if p.err != nil {
+			ps.done = true
+			return reflect.Value{}, false, p.err
+		}
+		ps.started = true
+		ps.items, ps.offset, ps.next = p.items, 0, p.next
+		if ps.opt.prefetch && ps.next != "" {
+			ch := make(chan page, 1)
+			go func(token string) { ch <- ps.fetchPage(token) }(ps.next)
+			ps.prefetch = ch
+		}
+	}
+	return reflect.Value{}, false, nil
+}
+
+func (ps *pageSource) nextPage() page {
+	if ch := ps.prefetch; ch != nil {
+		ps.prefetch = nil
+		return <-ch
+	}
+	return ps.fetchPage(ps.next)
+}
+
+func (ps *pageSource) fetchPage(token string) page {
+	for i := 0; ; i++ {
+		out := ps.fetch.Call([]reflect.Value{reflect.ValueOf(token).Convert(ps.fetch.Type().In(0))})
+		if err := out[2].Interface(); err == nil || err.(error) == nil {
+			return page{items: out[0], next: out[1].String()}
+		} else if i >= ps.opt.retry || !ps.opt.retryable(err.(error)) {
+			return page{err: err.(error)}
+		}
+		time.Sleep(ps.opt.backoff)
+	}
+}
@@ -8,7 +8,17 @@ func (q *stream) Partition(size int) Stream {
 	if size < 1 {
 		panic("batch size should be greater than 0")
 	}
-	return newStream(newCtx(q.ctx), reflect.SliceOf(q.expectElemTyp), q.iter, func(next iterator) iterator {
+	if src, ok := q.src.(*sliceSource); ok {
+		/* batches share memory with the source slice */
+		return newStream(newCtx(q.ctx), reflect.SliceOf(q.expectElemTyp), func() (reflect.Value, bool) {
+			if n := clamp(size, 0, src.Len()); n > 0 {
+				return src.take(n), true
+			}
+			return reflect.Value{}, false
+		})
+	}
+	ctx := q.derivedCtx()
+	return newStream(ctx, reflect.SliceOf(q.expectElemTyp), q.upstream(ctx), func(next iterator) iterator {
 		typ := reflect.SliceOf(q.expectElemTyp)
 		return func() (reflect.Value, bool) {
 			var slice reflect.Value
@@ -28,8 +38,11 @@ func (q *stream) Partition(size int) Stream {
 }
 
 func (q *stream) PartitionBy(fn interface{}, includeSplittor bool) Stream {
+	fn = resolvePredicate(fn, q.expectElemTyp)
+	assertPredicate("PartitionBy", fn, q.expectElemTyp)
 	fnval := reflect.ValueOf(fn)
-	return newStream(newCtx(q.ctx), reflect.SliceOf(q.expectElemTyp), q.iter, func(next iterator) iterator {
+	ctx := q.derivedCtx()
+	return newStream(ctx, reflect.SliceOf(q.expectElemTyp), q.upstream(ctx), func(next iterator) iterator {
 		typ := reflect.SliceOf(q.expectElemTyp)
 		return func() (reflect.Value, bool) {
 			var slice reflect.Value
@@ -61,8 +74,11 @@ func (q *stream) LPartitionBy(fn interface{}, includeSplittor bool) Stream {
 	if !includeSplittor {
 		return q.PartitionBy(fn, includeSplittor)
 	}
+	fn = resolvePredicate(fn, q.expectElemTyp)
+	assertPredicate("LPartitionBy", fn, q.expectElemTyp)
 	fnval := reflect.ValueOf(fn)
-	return newStream(newCtx(q.ctx), reflect.SliceOf(q.expectElemTyp), q.iter, func(next iterator) iterator {
+	ctx := q.derivedCtx()
+	return newStream(ctx, reflect.SliceOf(q.expectElemTyp), q.upstream(ctx), func(next iterator) iterator {
 		typ := reflect.SliceOf(q.expectElemTyp)
 		var lastHead reflect.Value
 		return func() (reflect.Value, bool) {
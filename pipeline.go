@@ -0,0 +1,172 @@
+package fp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Pipeline records operators without source, it could be applied to many streams
+type Pipeline struct {
+	/* inTyp/outTyp is nil if unknown yet */
+	inTyp, outTyp reflect.Type
+	/* sameTyp means output element type is same as input element type */
+	sameTyp bool
+	stages  []pipeStage
+}
+
+type pipeStage func(Stream) Stream
+
+// Pipe create an empty pipeline, element type is inferred from the first function
+func Pipe() Pipeline {
+	return Pipeline{sameTyp: true}
+}
+
+// Apply pipeline to stream
+func (p Pipeline) Apply(s Stream) Stream {
+	if typ := s.ToSource().ElemType(); p.inTyp != nil && typ != nil && !typ.AssignableTo(p.inTyp) {
+		panic(fmt.Sprintf("fp: Pipeline expects stream of %v, got %v", p.inTyp, typ))
+	}
+	for _, stage := range p.stages {
+		s = stage(s)
+	}
+	return s
+}
+
+// Then append stages of other pipeline
+func (p Pipeline) Then(other Pipeline) Pipeline {
+	if p.outTyp != nil && other.inTyp != nil && !p.outTyp.AssignableTo(other.inTyp) {
+		panic(fmt.Sprintf("fp: Pipeline.Then expects pipeline of %v, got %v", p.outTyp, other.inTyp))
+	}
+	np := p
+	if other.inTyp != nil {
+		np = np.learn(other.inTyp)
+	}
+	np.stages = append(append([]pipeStage{}, p.stages...), other.stages...)
+	if !other.sameTyp {
+		np.outTyp, np.sameTyp = other.outTyp, false
+	}
+	return np
+}
+
+func (p Pipeline) Map(fn interface{}, opts ...MapOption) Pipeline {
+	fn = resolveSelector(fn, p.outTyp)
+	assertMapper("Pipeline.Map", fn, p.outTyp)
+	return p.learn(reflect.TypeOf(fn).In(0)).to(reflect.TypeOf(fn).Out(0), func(s Stream) Stream {
+		return s.Map(fn, opts...)
+	})
+}
+
+func (p Pipeline) FlatMap(fn interface{}) Pipeline {
+	return p.Map(fn).Flatten()
+}
+
+func (p Pipeline) Filter(fn interface{}) Pipeline {
+	fn = resolvePredicate(fn, p.outTyp)
+	assertPredicate("Pipeline.Filter", fn, p.outTyp)
+	return p.learn(reflect.TypeOf(fn).In(0)).keep(func(s Stream) Stream { return s.Filter(fn) })
+}
+
+func (p Pipeline) Reject(fn interface{}) Pipeline {
+	fn = resolvePredicate(fn, p.outTyp)
+	assertPredicate("Pipeline.Reject", fn, p.outTyp)
+	return p.learn(reflect.TypeOf(fn).In(0)).keep(func(s Stream) Stream { return s.Reject(fn) })
+}
+
+func (p Pipeline) Foreach(fn interface{}) Pipeline {
+	assertFunc("Pipeline.Foreach", fn, sig(types(p.outTyp)), sig(types(p.outTyp, intType)))
+	return p.learn(reflect.TypeOf(fn).In(0)).keep(func(s Stream) Stream { return s.Foreach(fn) })
+}
+
+func (p Pipeline) TakeWhile(fn interface{}) Pipeline {
+	fn = resolvePredicate(fn, p.outTyp)
+	assertPredicate("Pipeline.TakeWhile", fn, p.outTyp)
+	return p.learn(reflect.TypeOf(fn).In(0)).keep(func(s Stream) Stream { return s.TakeWhile(fn) })
+}
+
+func (p Pipeline) SkipWhile(fn interface{}) Pipeline {
+	fn = resolvePredicate(fn, p.outTyp)
+	assertPredicate("Pipeline.SkipWhile", fn, p.outTyp)
+	return p.learn(reflect.TypeOf(fn).In(0)).keep(func(s Stream) Stream { return s.SkipWhile(fn) })
+}
+
+func (p Pipeline) SortBy(fn interface{}) Pipeline {
+	fn = resolveLess(fn, p.outTyp)
+	assertPredicate("Pipeline.SortBy", fn, p.outTyp, p.outTyp)
+	return p.learn(reflect.TypeOf(fn).In(0)).keep(func(s Stream) Stream { return s.SortBy(fn) })
+}
+
+func (p Pipeline) UniqBy(fn interface{}) Pipeline {
+	fn = resolveSelector(fn, p.outTyp)
+	assertKeyFunc("Pipeline.UniqBy", fn, p.outTyp)
+	return p.learn(reflect.TypeOf(fn).In(0)).keep(func(s Stream) Stream { return s.UniqBy(fn) })
+}
+
+func (p Pipeline) Take(n int) Pipeline {
+	return p.keep(func(s Stream) Stream { return s.Take(n) })
+}
+
+func (p Pipeline) Skip(n int) Pipeline {
+	return p.keep(func(s Stream) Stream { return s.Skip(n) })
+}
+
+func (p Pipeline) Sort() Pipeline {
+	return p.keep(func(s Stream) Stream { return s.Sort() })
+}
+
+func (p Pipeline) Uniq() Pipeline {
+	return p.keep(func(s Stream) Stream { return s.Uniq() })
+}
+
+func (p Pipeline) Reverse() Pipeline {
+	return p.keep(func(s Stream) Stream { return s.Reverse() })
+}
+
+func (p Pipeline) SafeMode() Pipeline {
+	return p.keep(func(s Stream) Stream { return s.SafeMode() })
+}
+
+func (p Pipeline) Partition(size int) Pipeline {
+	if size < 1 {
+		panic("batch size should be greater than 0")
+	}
+	var typ reflect.Type
+	if p.outTyp != nil {
+		typ = reflect.SliceOf(p.outTyp)
+	}
+	return p.to(typ, func(s Stream) Stream { return s.Partition(size) })
+}
+
+func (p Pipeline) Flatten() Pipeline {
+	var typ reflect.Type
+	if p.outTyp == nil || p.outTyp == streamType {
+	} else if kind := p.outTyp.Kind(); kind != reflect.Chan && kind != reflect.Slice && kind != reflect.Array {
+		panic(p.outTyp.String() + " can not be flatten")
+	} else {
+		typ = p.outTyp.Elem()
+	}
+	return p.to(typ, func(s Stream) Stream { return s.Flatten() })
+}
+
+/* learn element type from function input, interface input tells nothing about element type */
+func (p Pipeline) learn(typ reflect.Type) Pipeline {
+	if p.outTyp == nil && typ.Kind() != reflect.Interface {
+		p.outTyp = typ
+		if p.sameTyp && p.inTyp == nil {
+			p.inTyp = typ
+		}
+	}
+	return p
+}
+
+/* keep append stage which keeps element type */
+func (p Pipeline) keep(stage pipeStage) Pipeline {
+	p.stages = append(append([]pipeStage{}, p.stages...), stage)
+	return p
+}
+
+/* to append stage which transforms element to typ */
+func (p Pipeline) to(typ reflect.Type, stage pipeStage) Pipeline {
+	p = p.keep(stage)
+	p.outTyp, p.sameTyp = typ, false
+	return p
+}
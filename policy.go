@@ -0,0 +1,142 @@
+package fp
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// Backoff tells how long to wait before retry attempt, attempt starts from 0
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff wait d before every retry
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration { return d }
+}
+
+// ExponentialBackoff wait base, 2*base, 4*base... at most max
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 0; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// Jitter randomize backoff into [d/2, d]
+func (b Backoff) Jitter() Backoff {
+	return func(attempt int) time.Duration {
+		d := b(attempt)
+		if half := int64(d / 2); half > 0 {
+			return time.Duration(half + rand.Int63n(half+1))
+		}
+		return d
+	}
+}
+
+// Clock is the time source of retry and timeout, replace it in tests
+type Clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Retry failed map function at most times, backoff could be nil
+func Retry(times int, backoff Backoff) MapOption {
+	return func(opt *mapOption) {
+		opt.retries = times
+		opt.backoff = backoff
+	}
+}
+
+// RetryIf only retry errors satisfying fn
+func RetryIf(fn func(error) bool) MapOption {
+	return func(opt *mapOption) { opt.retryable = fn }
+}
+
+// Timeout fail map function with ErrTimeout if an attempt takes longer than d
+// the attempt runs on its own goroutine which is abandoned on timeout rather than cancelled,
+// so fn keeps running in background until it returns, fn that may block forever leaks its goroutine
+func Timeout(d time.Duration) MapOption {
+	return func(opt *mapOption) { opt.timeout = d }
+}
+
+// WithClock set clock used by Retry and Timeout
+func WithClock(c Clock) MapOption {
+	return func(opt *mapOption) { opt.clock = c }
+}
+
+/* guard apply timeout and retry policy to fn, fn must return error as last result */
+func (opt *mapOption) guard(op string, fnVal reflect.Value) reflect.Value {
+	if opt.retries <= 0 && opt.timeout <= 0 {
+		return fnVal
+	}
+	typ := fnVal.Type()
+	if typ.NumOut() == 0 || typ.Out(typ.NumOut()-1) != errType {
+		panic(fmt.Sprintf("fp: %s with Retry/Timeout expects function returning error, got %v", op, typ))
+	}
+	clock := opt.clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+	call := fnVal.Call
+	if opt.timeout > 0 {
+		call = func(in []reflect.Value) []reflect.Value {
+			return callWithTimeout(clock, opt.timeout, fnVal, in)
+		}
+	}
+	return reflect.MakeFunc(typ, func(in []reflect.Value) []reflect.Value {
+		for attempt := 0; ; attempt++ {
+			out := call(in)
+			err, _ := out[len(out)-1].Interface().(error)
+			if err == nil || attempt >= opt.retries || (opt.retryable != nil && !opt.retryable(err)) {
+				return out
+			}
+			if opt.backoff != nil {
+				<-clock.After(opt.backoff(attempt))
+			}
+		}
+	})
+}
+
+/* callWithTimeout abandon fn if it is not done in time, panic of fn is passed to caller */
+func callWithTimeout(clock Clock, d time.Duration, fnVal reflect.Value, in []reflect.Value) []reflect.Value {
+	type result struct {
+		out []reflect.Value
+		r   interface{}
+	}
+	done := make(chan result, 1)
+	/* caller may reuse in for next element while abandoned call is still starting */
+	in = append([]reflect.Value(nil), in...)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{r: r}
+			}
+		}()
+		done <- result{out: fnVal.Call(in)}
+	}()
+	select {
+	case res := <-done:
+		if res.r != nil {
+			panic(res.r)
+		}
+		return res.out
+	case <-clock.After(d):
+		typ := fnVal.Type()
+		out := make([]reflect.Value, typ.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(typ.Out(i))
+		}
+		out[len(out)-1] = reflect.ValueOf(ErrTimeout)
+		return out
+	}
+}
@@ -0,0 +1,198 @@
+package fp
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+/* predicates for filter, they could be composed by And/Or/Not */
+
+// In return a function like func(same_type_of_values) bool, true if input equals any of values
+func In(values ...interface{}) interface{} {
+	if len(values) == 0 {
+		panic("fp: In expects at least one value")
+	}
+	return makePredicate(reflect.TypeOf(values[0]), func(v reflect.Value) bool {
+		for _, e := range values {
+			if reflect.DeepEqual(v.Interface(), e) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Between return a function like func(same_type_of_lo) bool, true if lo <= input <= hi
+func Between(lo, hi interface{}) interface{} {
+	typ := reflect.TypeOf(lo)
+	if reflect.TypeOf(hi) != typ {
+		panic(fmt.Sprintf("fp: Between expects bounds of same type, got %v and %v", typ, reflect.TypeOf(hi)))
+	}
+	low, high := reflect.ValueOf(lo), reflect.ValueOf(hi)
+	return makePredicate(typ, func(v reflect.Value) bool {
+		return compareValue(typ, low, v) <= 0 && compareValue(typ, v, high) <= 0
+	})
+}
+
+// GreaterThan return a function like func(same_type_of_x) bool, true if input > x
+func GreaterThan(x interface{}) interface{} {
+	typ, xv := reflect.TypeOf(x), reflect.ValueOf(x)
+	return makePredicate(typ, func(v reflect.Value) bool {
+		return compareValue(typ, v, xv) > 0
+	})
+}
+
+// LessThan return a function like func(same_type_of_x) bool, true if input < x
+func LessThan(x interface{}) interface{} {
+	typ, xv := reflect.TypeOf(x), reflect.ValueOf(x)
+	return makePredicate(typ, func(v reflect.Value) bool {
+		return compareValue(typ, v, xv) < 0
+	})
+}
+
+// Predicate is resolved against element type when the operator is called like Selector does, so it works with
+// named types. It could be used by Filter/Reject/TakeWhile/SkipWhile/ContainsBy/PartitionBy/When and composed by And/Or/Not
+type Predicate struct {
+	/* compile return test of values of typ, it panics if typ is not supported */
+	compile func(typ reflect.Type) func(reflect.Value) bool
+}
+
+/* resolve predicate to func(typ) bool, fn which is not predicate is returned as is */
+func resolvePredicate(fn interface{}, typ reflect.Type) interface{} {
+	p, ok := fn.(Predicate)
+	if !ok || typ == nil {
+		return fn
+	}
+	return makePredicate(typ, p.compile(typ))
+}
+
+/* stringPredicate test elements of string kind */
+func stringPredicate(name string, fn func(string) bool) Predicate {
+	return Predicate{compile: func(typ reflect.Type) func(reflect.Value) bool {
+		if typ.Kind() != reflect.String {
+			panic(fmt.Sprintf("fp: %s expects element of string kind, got %v", name, typ))
+		}
+		return func(v reflect.Value) bool { return fn(v.String()) }
+	}}
+}
+
+// HasPrefix works with stream of string kind
+func HasPrefix(prefix string) Predicate {
+	return stringPredicate("HasPrefix", func(s string) bool { return strings.HasPrefix(s, prefix) })
+}
+
+// HasSuffix works with stream of string kind
+func HasSuffix(suffix string) Predicate {
+	return stringPredicate("HasSuffix", func(s string) bool { return strings.HasSuffix(s, suffix) })
+}
+
+// Contains works with stream of string kind
+func Contains(substr string) Predicate {
+	return stringPredicate("Contains", func(s string) bool { return strings.Contains(s, substr) })
+}
+
+// MatchRegexp works with stream of string kind, it panics if expr is not a valid regular expression
+func MatchRegexp(expr string) Predicate {
+	return stringPredicate("MatchRegexp", regexp.MustCompile(expr).MatchString)
+}
+
+// IsZero works with stream of any type, dynamic value of interface element is checked
+func IsZero() Predicate {
+	return Predicate{compile: func(reflect.Type) func(reflect.Value) bool {
+		return func(v reflect.Value) bool {
+			if v.Kind() == reflect.Interface {
+				v = v.Elem()
+			}
+			return !v.IsValid() || v.IsZero()
+		}
+	}}
+}
+
+// IsNil works with stream of any type, true for nil pointer/map/slice/chan/func/interface
+func IsNil() Predicate {
+	return Predicate{compile: func(reflect.Type) func(reflect.Value) bool {
+		return func(v reflect.Value) bool { return isNilObject(v.Interface()) }
+	}}
+}
+
+// Field apply predicate to struct field or map key name of input, name could be a path like "Address.City",
+// pointers are dereferenced. It is validated against element type when the operator is called:
+// missing struct field or field type not matching predicate panics, missing map key is false.
+// Elements of interface type are validated against their dynamic type when it is first seen
+func Field(name string, predicate interface{}) Predicate {
+	predicate = resolveConditionFunc(predicate)
+	if _, ok := predicate.(Predicate); !ok {
+		assertPredicate("Field", predicate, nil)
+	}
+	return Predicate{compile: func(typ reflect.Type) func(reflect.Value) bool {
+		if typ.Kind() != reflect.Interface {
+			return compileField(name, typ, predicate)
+		}
+		/* tests compiled by dynamic type of input */
+		var tests sync.Map
+		return func(v reflect.Value) bool {
+			if v = v.Elem(); !v.IsValid() {
+				return false
+			}
+			test, ok := tests.Load(v.Type())
+			if !ok {
+				test, _ = tests.LoadOrStore(v.Type(), compileField(name, v.Type(), predicate))
+			}
+			return test.(func(reflect.Value) bool)(v)
+		}
+	}}
+}
+
+/* compileField return test of predicate on field name of typ, it is false if field is absent */
+func compileField(name string, typ reflect.Type, predicate interface{}) func(reflect.Value) bool {
+	get, ftyp, err := compilePath(typ, strings.Split(name, "."))
+	if err != nil {
+		panic(fmt.Sprintf("fp: Field %s of %v: %v", name, typ, err))
+	}
+	if p, ok := predicate.(Predicate); ok {
+		test := p.compile(ftyp)
+		return func(v reflect.Value) bool {
+			field := get(v)
+			return field.IsValid() && test(field)
+		}
+	}
+	fn := reflect.ValueOf(predicate)
+	inTyp := fn.Type().In(0)
+	convert := func(field reflect.Value) reflect.Value {
+		if field.Type().AssignableTo(inTyp) {
+			return field
+		}
+		if field.Kind() != inTyp.Kind() || !field.Type().ConvertibleTo(inTyp) {
+			panic(fmt.Sprintf("fp: Field %s of %v is %v, predicate expects %v", name, typ, field.Type(), inTyp))
+		}
+		return field.Convert(inTyp)
+	}
+	if ftyp.Kind() != reflect.Interface || inTyp.Kind() == reflect.Interface {
+		/* static field type is checked right now */
+		convert(reflect.Zero(ftyp))
+		return func(v reflect.Value) bool {
+			field := get(v)
+			if !field.IsValid() {
+				return false
+			}
+			return fn.Call([]reflect.Value{convert(field)})[0].Bool()
+		}
+	}
+	return func(v reflect.Value) bool {
+		field := get(v)
+		if !field.IsValid() || field.IsNil() {
+			return false
+		}
+		return fn.Call([]reflect.Value{convert(field.Elem())})[0].Bool()
+	}
+}
+
+/* makePredicate make func(typ) bool */
+func makePredicate(typ reflect.Type, fn func(reflect.Value) bool) interface{} {
+	return reflect.MakeFunc(reflect.FuncOf([]reflect.Type{typ}, []reflect.Type{boolType}, false), func(in []reflect.Value) []reflect.Value {
+		return []reflect.Value{reflect.ValueOf(fn(in[0]))}
+	}).Interface()
+}
@@ -14,7 +14,8 @@ func (q *stream) Prepend(v ...interface{}) Stream {
 }
 
 func (q *stream) prependOne(v interface{}) *stream {
-	return newStream(newCtx(q.ctx), q.expectElemTyp, q.iter, func(next iterator) iterator {
+	ctx := q.derivedCtx()
+	return newStream(ctx, q.expectElemTyp, q.upstream(ctx), func(next iterator) iterator {
 		var flag int32
 		return func() (reflect.Value, bool) {
 			if atomic.CompareAndSwapInt32(&flag, 0, 1) {
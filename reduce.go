@@ -3,11 +3,25 @@ package fp
 import "reflect"
 
 func (q *stream) Reduce(initval interface{}, fn interface{}) Value {
+	q.rewind()
+	return q.reduce(initval, fn)
+}
+
+func (q *stream) reduce(initval interface{}, fn interface{}) (res Value) {
 	typ := reflect.TypeOf(initval)
+	assertFunc("Reduce", fn, sig(types(typ, q.expectElemTyp), typ))
 	memo := reflect.ValueOf(initval)
 	fnval := reflect.ValueOf(fn)
+	var val reflect.Value
+	defer func() {
+		if err := q.ctx.Err(); err != nil && res.err == nil {
+			res = Value{typ: typ, err: err}
+		}
+	}()
+	defer recoverErr(q.ctx, &val)
 	for {
-		val, ok := q.iter()
+		var ok bool
+		val, ok = q.iter()
 		if !ok {
 			break
 		}
@@ -17,9 +31,11 @@ func (q *stream) Reduce(initval interface{}, fn interface{}) Value {
 }
 
 func (q *stream) Reduce0(fn interface{}) Value {
+	q.rewind()
 	initVal, ok := q.iter()
 	if !ok {
+		q.finish(true)
 		return Value{typ: q.expectElemTyp, val: reflect.Zero(q.expectElemTyp), err: q.ctx.Err()}
 	}
-	return q.Reduce(initVal.Interface(), fn)
+	return q.reduce(initVal.Interface(), fn)
 }
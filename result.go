@@ -11,6 +11,7 @@ func (q *stream) ToSlice(dst interface{}) error {
 		panic(`fp: dst must be pointer`)
 	}
 	val.Elem().Set(q.getValue(val.Elem()))
+	q.finish(true)
 	return q.ctx.Err()
 }
 
@@ -28,6 +29,10 @@ func (q *stream) getResult() Value {
 }
 
 func (q *stream) Run() {
+	if q.fork != nil {
+		/* elements of cached stream are replayed by other terminal ops */
+		q.getValue(reflect.Value{})
+	}
 	q.getValOnce.Do(func() {
 		for {
 			if _, ok := q.iter(); !ok {
@@ -35,10 +40,11 @@ func (q *stream) Run() {
 			}
 		}
 	})
+	q.finish(true)
 }
 
 func (q *stream) Error() error {
-	if q.expectElemTyp.AssignableTo(errType) {
+	if q.expectElemTyp != nil && q.expectElemTyp.AssignableTo(errType) {
 		return q.SkipWhile(NoError()).First().Err()
 	}
 	q.Run()
@@ -47,11 +53,21 @@ func (q *stream) Error() error {
 
 func (q *stream) getValue(slice reflect.Value) reflect.Value {
 	q.getValOnce.Do(func() {
+		q.rewind()
+		/* src is drained below, shortcuts should not use it anymore */
+		src := q.src
+		q.src = nil
 		if !slice.IsValid() {
 			slice = reflect.Zero(reflect.SliceOf(q.expectElemTyp))
 		} else if slice.Len() > 0 {
 			slice = reflect.MakeSlice(reflect.SliceOf(q.expectElemTyp), 0, slice.Len())
 		}
+		if ss, ok := src.(*sliceSource); ok {
+			/* copy at once */
+			slice = reflect.AppendSlice(slice, ss.take(ss.Len()))
+		} else if sized, ok := src.(SizedSource); ok && slice.Cap() == 0 && sized.Len() > 0 {
+			slice = reflect.MakeSlice(reflect.SliceOf(q.expectElemTyp), 0, sized.Len())
+		}
 		for {
 			if val, ok := q.iter(); ok {
 				slice = reflect.Append(slice, val)
@@ -64,13 +80,20 @@ func (q *stream) getValue(slice reflect.Value) reflect.Value {
 	return q.val
 }
 
-func (q *stream) Strings() (s []string)       { return q.getResult().Strings() }
-func (q *stream) Ints() (s []int)             { return q.getResult().Ints() }
-func (q *stream) Int64s() (s []int64)         { return q.getResult().Int64s() }
-func (q *stream) Int32s() (s []int32)         { return q.getResult().Int32s() }
-func (q *stream) Uints() (s []uint)           { return q.getResult().Uints() }
-func (q *stream) Uint32s() (s []uint32)       { return q.getResult().Uint32s() }
-func (q *stream) Uint64s() (s []uint64)       { return q.getResult().Uint64s() }
-func (q *stream) Bytes() (s []byte)           { return q.getResult().Bytes() }
-func (q *stream) Float64s() (s []float64)     { q.getResult().To(&s); return }
-func (q *stream) StringsList() (s [][]string) { return q.getResult().StringsList() }
+/* result is getResult of a terminal op, it finishes stream */
+func (q *stream) result() Value {
+	q.getValue(reflect.Value{})
+	q.finish(true)
+	return q.getResult()
+}
+
+func (q *stream) Strings() (s []string)       { return q.result().Strings() }
+func (q *stream) Ints() (s []int)             { return q.result().Ints() }
+func (q *stream) Int64s() (s []int64)         { return q.result().Int64s() }
+func (q *stream) Int32s() (s []int32)         { return q.result().Int32s() }
+func (q *stream) Uints() (s []uint)           { return q.result().Uints() }
+func (q *stream) Uint32s() (s []uint32)       { return q.result().Uint32s() }
+func (q *stream) Uint64s() (s []uint64)       { return q.result().Uint64s() }
+func (q *stream) Bytes() (s []byte)           { return q.result().Bytes() }
+func (q *stream) Float64s() (s []float64)     { q.result().To(&s); return }
+func (q *stream) StringsList() (s [][]string) { return q.result().StringsList() }
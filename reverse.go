@@ -1,8 +1,14 @@
 package fp
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+)
 
 func (q *stream) Reverse() Stream {
+	if src, ok := q.randomAccess(); ok {
+		return q.view(&reversedSource{src: src.Slice(0, src.Len()), parent: src})
+	}
 	var iter iterator
 	return newStream(newCtx(q.ctx), q.expectElemTyp, func() (reflect.Value, bool) {
 		if iter == nil {
@@ -20,3 +26,49 @@ func (q *stream) Reverse() Stream {
 		return iter()
 	})
 }
+
+/* reversedSource read src backwards, taken elements are consumed from the end of src,
+ * parent is src of stream reversed, it's committed once reversedSource is read like collecting stream would
+ */
+type reversedSource struct {
+	src     RandomAccessSource
+	taken   int
+	parent  Source
+	started bool
+}
+
+func (rs *reversedSource) ElemType() reflect.Type { return rs.src.ElemType() }
+
+func (rs *reversedSource) Next() (reflect.Value, bool) {
+	rs.start()
+	if rs.Len() == 0 {
+		return reflect.Value{}, false
+	}
+	val := rs.At(0)
+	rs.taken++
+	return val, true
+}
+
+func (rs *reversedSource) advance(n int) {
+	rs.start()
+	rs.taken += clamp(n, 0, rs.Len())
+}
+
+func (rs *reversedSource) start() {
+	if !rs.started && rs.parent != nil {
+		commit(rs.parent)
+	}
+	rs.started = true
+}
+
+func (rs *reversedSource) Len() int { return rs.src.Len() - rs.taken }
+
+func (rs *reversedSource) At(i int) reflect.Value { return rs.src.At(rs.Len() - 1 - i) }
+
+func (rs *reversedSource) Slice(from, to int) RandomAccessSource {
+	n := rs.Len()
+	if from < 0 || from > to || to > n {
+		panic(fmt.Sprintf("fp: slice [%d:%d] out of range with length %d", from, to, n))
+	}
+	return &reversedSource{src: rs.src.Slice(n-to, n-from)}
+}
@@ -0,0 +1,71 @@
+package fp
+
+import (
+	"reflect"
+	"runtime/debug"
+)
+
+func (q *stream) SafeMode() Stream {
+	ctx := newSafeCtx(q.derivedCtx())
+	return newStream(ctx, q.expectElemTyp, q.upstream(ctx))
+}
+
+func (obj *kvStream) SafeMode() KVStream {
+	return newKvStream(newSafeCtx(obj.ctx), obj.keyType, obj.valType, obj.getTable)
+}
+
+/* recoverIter convert panic of it into error of ctx, the last element pulled from upstream is the suspect */
+func recoverIter(ctx context, upstream iterator, build func(iterator) iterator) iterator {
+	var last reflect.Value
+	var failed bool
+	it := build(func() (reflect.Value, bool) {
+		val, ok := upstream()
+		if ok {
+			last = val
+		}
+		return val, ok
+	})
+	return func() (val reflect.Value, ok bool) {
+		if failed {
+			return reflect.Value{}, false
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				failed = true
+				ctx.SetErr(newPanicError(r, last))
+				val, ok = reflect.Value{}, false
+			}
+		}()
+		return it()
+	}
+}
+
+/* recoverErr store panic into ctx if ctx is in safe mode, it must be deferred directly */
+func recoverErr(ctx context, elem *reflect.Value) {
+	if !ctx.Safe() {
+		return
+	}
+	if r := recover(); r != nil {
+		ctx.SetErr(newPanicError(r, *elem))
+	}
+}
+
+func newPanicError(r interface{}, elem reflect.Value) *PanicError {
+	err := &PanicError{Value: r, Stack: debug.Stack()}
+	if elem.IsValid() && elem.CanInterface() {
+		err.Element = elem.Interface()
+	}
+	return err
+}
+
+func recoverTable(ctx context, k, v reflect.Type, getTable func() kvTable) func() kvTable {
+	return func() (table kvTable) {
+		defer func() {
+			if r := recover(); r != nil {
+				ctx.SetErr(newPanicError(r, reflect.Value{}))
+				table = newKVTable(k, v)
+			}
+		}()
+		return getTable()
+	}
+}
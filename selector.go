@@ -0,0 +1,156 @@
+package fp
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Selector select value of element by path like "Address.City", "Tags.0" or "Labels.env".
+// It could be used as mapper of Map or key function of SortBy/GroupBy/UniqBy/ToSetBy/InteractBy/SubBy,
+// path is resolved against element type when the operator is called
+type Selector struct {
+	paths []string
+	/* project selects paths into map[string]interface{} */
+	project bool
+}
+
+// Pluck select value by path, pointers are dereferenced, absent map key/slice index/nil pointer results zero value
+func Pluck(path string) Selector {
+	return Selector{paths: []string{path}}
+}
+
+// Select project paths into map[string]interface{} keyed by path
+func Select(paths ...string) Selector {
+	if len(paths) == 0 {
+		panic("fp: Select expects at least one path")
+	}
+	return Selector{paths: paths, project: true}
+}
+
+/* resolve selector to func(typ) field_type, fn which is not selector is returned as is */
+func resolveSelector(fn interface{}, typ reflect.Type) interface{} {
+	s, ok := fn.(Selector)
+	if !ok || typ == nil {
+		return fn
+	}
+	return s.resolve(typ).Interface()
+}
+
+func (s Selector) resolve(typ reflect.Type) reflect.Value {
+	getters := make([]func(reflect.Value) reflect.Value, len(s.paths))
+	outTyp := anyType
+	for i, path := range s.paths {
+		get, ftyp, err := compilePath(typ, strings.Split(path, "."))
+		if err != nil {
+			panic(fmt.Sprintf("fp: bad selector %q of %v: %v", path, typ, err))
+		}
+		getters[i] = func(v reflect.Value) reflect.Value {
+			if out := get(v); out.IsValid() {
+				return out
+			}
+			return reflect.Zero(ftyp)
+		}
+		outTyp = ftyp
+	}
+	if s.project {
+		outTyp = reflect.TypeOf(map[string]interface{}{})
+	}
+	return reflect.MakeFunc(reflect.FuncOf([]reflect.Type{typ}, []reflect.Type{outTyp}, false), func(in []reflect.Value) []reflect.Value {
+		if !s.project {
+			return []reflect.Value{getters[0](in[0])}
+		}
+		out := make(map[string]interface{}, len(s.paths))
+		for i, path := range s.paths {
+			out[path] = getters[i](in[0]).Interface()
+		}
+		return []reflect.Value{reflect.ValueOf(out)}
+	})
+}
+
+/* compilePath return getter of path on typ and type of selected value, getter returns invalid value if value is absent */
+func compilePath(typ reflect.Type, path []string) (func(reflect.Value) reflect.Value, reflect.Type, error) {
+	var steps []func(reflect.Value) reflect.Value
+	run := func(v reflect.Value) reflect.Value {
+		for _, step := range steps {
+			if !v.IsValid() {
+				break
+			}
+			v = step(v)
+		}
+		return v
+	}
+	for i, seg := range path {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+			steps = append(steps, func(v reflect.Value) reflect.Value {
+				if v.IsNil() {
+					return reflect.Value{}
+				}
+				return v.Elem()
+			})
+		}
+		switch typ.Kind() {
+		case reflect.Interface:
+			/* remain path is resolved by dynamic type */
+			rest := path[i:]
+			steps = append(steps, func(v reflect.Value) reflect.Value {
+				if v.IsNil() {
+					return reflect.Value{}
+				}
+				get, _, err := compilePath(v.Elem().Type(), rest)
+				if err != nil {
+					return reflect.Value{}
+				}
+				return get(v.Elem())
+			})
+			return run, typ, nil
+		case reflect.Struct:
+			field, ok := typ.FieldByName(seg)
+			if !ok || field.PkgPath != "" {
+				return nil, nil, fmt.Errorf("no exported field %s in %v", seg, typ)
+			}
+			steps = append(steps, func(v reflect.Value) reflect.Value { return v.FieldByIndex(field.Index) })
+			typ = field.Type
+		case reflect.Map:
+			key, err := parseKey(seg, typ.Key())
+			if err != nil {
+				return nil, nil, err
+			}
+			steps = append(steps, func(v reflect.Value) reflect.Value { return v.MapIndex(key) })
+			typ = typ.Elem()
+		case reflect.Slice, reflect.Array:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 {
+				return nil, nil, fmt.Errorf("bad index %s of %v", seg, typ)
+			}
+			steps = append(steps, func(v reflect.Value) reflect.Value {
+				if idx >= v.Len() {
+					return reflect.Value{}
+				}
+				return v.Index(idx)
+			})
+			typ = typ.Elem()
+		default:
+			return nil, nil, fmt.Errorf("can not select %s from %v", seg, typ)
+		}
+	}
+	return run, typ, nil
+}
+
+func parseKey(seg string, typ reflect.Type) (reflect.Value, error) {
+	switch typ.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(seg).Convert(typ), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(seg, 10, typ.Bits()); err == nil {
+			return reflect.ValueOf(i).Convert(typ), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, err := strconv.ParseUint(seg, 10, typ.Bits()); err == nil {
+			return reflect.ValueOf(i).Convert(typ), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("bad key %s of %v", seg, typ)
+}
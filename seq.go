@@ -0,0 +1,125 @@
+//go:build go1.23
+
+package fp
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+type seqStream interface {
+	// Seq iterate stream by range-over-func, breaking early closes the stream
+	Seq() iter.Seq[interface{}]
+}
+
+type seqKVStream interface {
+	// Seq2 iterate key-value pairs by range-over-func
+	Seq2() iter.Seq2[interface{}, interface{}]
+}
+
+func (q *stream) Seq() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		s := q.consumer()
+		for {
+			val, ok := s.iter()
+			if !ok {
+				s.finish(true)
+				return
+			}
+			if !yield(val.Interface()) {
+				s.Close()
+				return
+			}
+		}
+	}
+}
+
+func (ns *nilStream) Seq() iter.Seq[interface{}] {
+	return func(func(interface{}) bool) { ns.done() }
+}
+
+func (obj *kvStream) Seq2() iter.Seq2[interface{}, interface{}] {
+	return func(yield func(interface{}, interface{}) bool) {
+		defer obj.finish()
+		next := obj.iter()
+		for k, v, ok := next(); ok; k, v, ok = next() {
+			if !yield(k.Interface(), v.Interface()) {
+				return
+			}
+		}
+	}
+}
+
+func (ks *nilkvStream) Seq2() iter.Seq2[interface{}, interface{}] {
+	return func(func(interface{}, interface{}) bool) { ks.done() }
+}
+
+// SeqOf typed iter.Seq of stream, it panics if elements of stream are not assignable to T
+func SeqOf[T any](s Stream) iter.Seq[T] {
+	if q, ok := s.(*stream); ok {
+		assertSeqType("SeqOf", q.expectElemTyp, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return func(yield func(T) bool) {
+		for v := range s.Seq() {
+			t, _ := v.(T)
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Seq2Of typed iter.Seq2 of kv stream, it panics if keys or values are not assignable to K or V
+func Seq2Of[K, V any](s KVStream) iter.Seq2[K, V] {
+	if obj, ok := s.(*kvStream); ok {
+		assertSeqType("Seq2Of", obj.keyType, reflect.TypeOf((*K)(nil)).Elem())
+		assertSeqType("Seq2Of", obj.valType, reflect.TypeOf((*V)(nil)).Elem())
+	}
+	return func(yield func(K, V) bool) {
+		for k, v := range s.Seq2() {
+			tk, _ := k.(K)
+			tv, _ := v.(V)
+			if !yield(tk, tv) {
+				return
+			}
+		}
+	}
+}
+
+func assertSeqType(name string, elemTyp, typ reflect.Type) {
+	if elemTyp != nil && !elemTyp.AssignableTo(typ) {
+		panic(fmt.Sprintf("fp: %s expects type parameter assignable from %v, got %v", name, elemTyp, typ))
+	}
+}
+
+/* seqIter pull elements from iter.Seq/iter.Seq2, elements of iter.Seq2 are Tuple */
+func seqIter(ctx context, val reflect.Value) (reflect.Type, iterator, bool) {
+	typ := val.Type()
+	var elemTyp reflect.Type
+	var toElem func([]reflect.Value) reflect.Value
+	if isSeqFunction(typ, 1) {
+		elemTyp = typ.In(0).In(0)
+		toElem = func(in []reflect.Value) reflect.Value { return in[0] }
+	} else if isSeqFunction(typ, 2) {
+		elemTyp = reflect.TypeOf(Tuple{})
+		toElem = func(in []reflect.Value) reflect.Value {
+			return reflect.ValueOf(TupleOf(in[0].Interface(), in[1].Interface()))
+		}
+	} else {
+		return nil, nil, false
+	}
+	next, stop := iter.Pull(func(yield func(reflect.Value) bool) {
+		val.Call([]reflect.Value{reflect.MakeFunc(typ.In(0), func(in []reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.ValueOf(yield(toElem(in)))}
+		})})
+	})
+	ctx.hooks().addCloser(closeFunc(stop))
+	return elemTyp, func() (reflect.Value, bool) {
+		v, ok := next()
+		if !ok {
+			stop()
+		}
+		return v, ok
+	}, true
+}
@@ -0,0 +1,13 @@
+//go:build !go1.23
+
+package fp
+
+import "reflect"
+
+type seqStream interface{}
+
+type seqKVStream interface{}
+
+func seqIter(ctx context, val reflect.Value) (reflect.Type, iterator, bool) {
+	return nil, nil, false
+}
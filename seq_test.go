@@ -0,0 +1,126 @@
+//go:build go1.23
+
+package fp
+
+import (
+	"io"
+	"strconv"
+)
+
+func (suite *TestFPTestSuite) TestSeq() {
+	var out []int
+	for v := range Times(5).Filter(func(i int) bool { return i%2 == 0 }).Seq() {
+		out = append(out, v.(int))
+	}
+	suite.Equal([]int{0, 2, 4}, out)
+
+	out = nil
+	for v := range SeqOf[int](Times(5)) {
+		out = append(out, v)
+	}
+	suite.Equal([]int{0, 1, 2, 3, 4}, out)
+
+	for range newNilStream().Seq() {
+		suite.Fail("nil stream should be empty")
+	}
+}
+
+func (suite *TestFPTestSuite) TestSeqBreakCloseStream() {
+	c := &closeRecorder{}
+	s := Using(func() (Source, io.Closer, error) {
+		return NewCounter(10), c, nil
+	})
+	var out []int
+	for v := range SeqOf[int](s) {
+		if v == 2 {
+			break
+		}
+		out = append(out, v)
+	}
+	suite.Equal([]int{0, 1}, out)
+	suite.Equal(1, c.closed)
+}
+
+func (suite *TestFPTestSuite) TestSeq2() {
+	m := map[string]int{"a": 1, "b": 2}
+	out := make(map[string]int)
+	for k, v := range KVStreamOf(m).Seq2() {
+		out[k.(string)] = v.(int)
+	}
+	suite.Equal(m, out)
+
+	out = make(map[string]int)
+	for k, v := range Seq2Of[string, int](KVStreamOf(m)) {
+		out[k] = v
+	}
+	suite.Equal(m, out)
+}
+
+func (suite *TestFPTestSuite) TestSeqOfTypeMismatch() {
+	suite.Panics(func() { SeqOf[string](Times(5)) })
+	suite.Panics(func() { Seq2Of[string, string](KVStreamOf(map[string]int{"a": 1})) })
+
+	var out []interface{}
+	for v := range SeqOf[interface{}](Times(2)) {
+		out = append(out, v)
+	}
+	suite.Equal([]interface{}{0, 1}, out)
+}
+
+func (suite *TestFPTestSuite) TestKVStreamOfSeq2IsLazy() {
+	var stopped bool
+	infinite := func(yield func(int, string) bool) {
+		defer func() { stopped = true }()
+		for i := 0; ; i++ {
+			if !yield(i, strconv.Itoa(i)) {
+				return
+			}
+		}
+	}
+	suite.Equal([]int{0, 1, 2}, KVStreamOf(infinite).Keys().Take(3).Ints())
+	suite.True(stopped)
+	suite.Equal([]string{"0", "1"}, KVStreamOf(infinite).Values().Take(2).Strings())
+
+	dup := func(yield func(string, int) bool) {
+		_ = yield("a", 1) && yield("a", 2) && yield("b", 3)
+	}
+	suite.Equal([]string{"a", "a", "b"}, KVStreamOf(dup).Keys().Strings())
+	var vals []int
+	for _, v := range Seq2Of[string, int](KVStreamOf(dup)) {
+		vals = append(vals, v)
+	}
+	suite.Equal([]int{1, 2, 3}, vals)
+	suite.Equal(2, KVStreamOf(dup).Size())
+}
+
+func (suite *TestFPTestSuite) TestStreamOfSeq() {
+	seq := func(yield func(int) bool) {
+		for i := 0; i < 5; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	suite.Equal([]string{"1", "3"}, StreamOf(seq).Filter(func(i int) bool { return i%2 == 1 }).Map(strconv.Itoa).Strings())
+
+	var stopped bool
+	infinite := func(yield func(int) bool) {
+		defer func() { stopped = true }()
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	suite.Equal([]int{0, 1, 2}, StreamOf(infinite).Take(3).Ints())
+	suite.True(stopped)
+
+	seq2 := func(yield func(string, int) bool) {
+		yield("a", 1)
+	}
+	suite.Equal([]Tuple{TupleOf("a", 1)}, func() (out []Tuple) {
+		StreamOf(seq2).ToSlice(&out)
+		return
+	}())
+	suite.Equal([]string{"a"}, KVStreamOf(seq2).Keys().Strings())
+}
@@ -0,0 +1,117 @@
+package fp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Sequence turn stream of Monad into Monad of slice, it fails on the first failed monad and is empty if any monad is empty.
+// Stream is consumed once when monad is evaluated first time. Slice type is known upfront if stream reads from slice,
+// otherwise the monad is of interface{} holding the slice
+func Sequence(s Stream) Monad {
+	if typ := s.ToSource().ElemType(); typ != nil && !typ.Implements(monadType) {
+		panic(fmt.Sprintf("fp: Sequence expects stream of Monad, got %v", typ))
+	}
+	outTyp := anyType
+	if q, ok := s.(*stream); ok {
+		if src, ok := q.randomAccess(); ok {
+			/* monads are read without consuming stream */
+			monads := make([]Monad, src.Len())
+			for i := range monads {
+				monads[i], _ = src.At(i).Interface().(Monad)
+			}
+			outTyp = reflect.SliceOf(valueTypeOfAll(monads))
+		}
+	}
+	return newErrorMonad(reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{outTyp, boolType, errType}, false), func([]reflect.Value) []reflect.Value {
+		fail := func(err error) []reflect.Value {
+			return []reflect.Value{reflect.Zero(outTyp), reflect.ValueOf(false), errValue(err)}
+		}
+		var monads []Monad
+		if err := s.ToSlice(&monads); err != nil {
+			return fail(err)
+		}
+		elemTyp := valueTypeOfAll(monads)
+		list := reflect.MakeSlice(reflect.SliceOf(elemTyp), 0, len(monads))
+		for _, m := range monads {
+			v, ok, err := m.fnContainer()()
+			if err != nil || !ok {
+				return fail(err)
+			}
+			list = reflect.Append(list, convertTo(reflect.ValueOf(v), elemTyp))
+		}
+		return []reflect.Value{convertTo(list, outTyp), reflect.ValueOf(true), reflect.Zero(errType)}
+	})).Once()
+}
+
+// Traverse map every element of stream by fn and collect results as Monad of slice,
+// fn is kind of func(element_type) (type1,&optional error/bool); stream is consumed once when monad is evaluated first time
+func Traverse(s Stream, fn interface{}) Monad {
+	assertMapper("Traverse", fn, s.ToSource().ElemType())
+	fnVal := toErrMonadFunc(fn)
+	sliceTyp := reflect.SliceOf(fnVal.Type().Out(0))
+	return newErrorMonad(reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{sliceTyp, boolType, errType}, false), func([]reflect.Value) []reflect.Value {
+		fail := func(ok bool, err error) []reflect.Value {
+			return []reflect.Value{reflect.Zero(sliceTyp), reflect.ValueOf(ok), errValue(err)}
+		}
+		it := s.Iterator()
+		defer it.Close()
+		inTyp := fnVal.Type().In(0)
+		list := reflect.MakeSlice(sliceTyp, 0, 0)
+		for it.Next() {
+			out := fnVal.Call([]reflect.Value{convertTo(reflect.ValueOf(it.Value()), inTyp)})
+			if e := out[2].Interface(); e != nil && e.(error) != nil {
+				return fail(false, e.(error))
+			}
+			if !out[1].Bool() {
+				return fail(false, nil)
+			}
+			list = reflect.Append(list, out[0])
+		}
+		if err := it.Err(); err != nil {
+			return fail(false, err)
+		}
+		return []reflect.Value{list, reflect.ValueOf(true), reflect.Zero(errType)}
+	})).Once()
+}
+
+func (q *stream) FirstM() Monad {
+	f := q.First()
+	if f.err != nil {
+		return newNilMonad(f.err)
+	}
+	if !f.val.IsValid() {
+		return newNilMonad(nil)
+	}
+	return newErrorMonad(reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{f.typ, boolType, errType}, false), func([]reflect.Value) []reflect.Value {
+		return []reflect.Value{f.val, reflect.ValueOf(true), reflect.Zero(errType)}
+	}))
+}
+
+/* valueTypeOfAll return common value type of monads, interface{} if they differ or are unknown */
+func valueTypeOfAll(monads []Monad) reflect.Type {
+	var elemTyp reflect.Type
+	for _, m := range monads {
+		if typ := valueTypeOf(m); typ == nil {
+		} else if elemTyp == nil {
+			elemTyp = typ
+		} else if elemTyp != typ {
+			return anyType
+		}
+	}
+	if elemTyp == nil {
+		return anyType
+	}
+	return elemTyp
+}
+
+/* valueTypeOf return value type of monad, nil if unknown */
+func valueTypeOf(m Monad) reflect.Type {
+	switch em := m.(type) {
+	case errorMonad:
+		return em.fn.Type().Out(0)
+	case asyncMonad:
+		return em.fn.Type().Out(0)
+	}
+	return nil
+}
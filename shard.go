@@ -0,0 +1,185 @@
+package fp
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+/* shardBuffer is the number of elements buffered by each shard, dispatching blocks when a shard is full */
+const shardBuffer = 16
+
+// Shard split stream into n streams, elements are routed round-robin if keyFn is nil,
+// otherwise by hash of keyFn(element) so elements of the same key go to the same shard
+// shards are fed by one goroutine with bounded buffers, so they should be consumed concurrently,
+// a shard that is never read blocks the others once its buffer is full, close it if it's not needed
+func (q *stream) Shard(n int, keyFn interface{}) []Stream {
+	_, shards := q.shard(n, keyFn)
+	return shards
+}
+
+// Distribute run worker on each of n shards concurrently and wait them all,
+// it returns the error of stream or workers, multiple errors are reported as MultiError
+func (q *stream) Distribute(n int, keyFn interface{}, worker StreamProcessor) error {
+	d, shards := q.shard(n, keyFn)
+	return distribute(shards, worker, d.error)
+}
+
+func (q *stream) shard(n int, keyFn interface{}) (*dispatcher, []Stream) {
+	if n < 1 {
+		panic("shard number should be greater than 0")
+	}
+	q = q.consumer()
+	d := &dispatcher{q: q, next: q.iter, shards: make([]*shard, n)}
+	if keyFn != nil {
+		keyFn = resolveSelector(keyFn, q.expectElemTyp)
+		assertKeyFunc("Shard", keyFn, q.expectElemTyp)
+		d.key = mapperOf(keyFn)
+	}
+	streams := make([]Stream, n)
+	for i := range d.shards {
+		sh := &shard{ch: make(chan reflect.Value, shardBuffer), quit: make(chan struct{})}
+		d.shards[i] = sh
+		ctx := newCtx(nil)
+		ctx.hooks().addCloser(closeFunc(func() { d.abandon(sh) }))
+		streams[i] = newStream(ctx, q.expectElemTyp, func() (reflect.Value, bool) {
+			d.startOnce.Do(func() { go d.run() })
+			if val, ok := <-sh.ch; ok {
+				return val, true
+			}
+			if err := d.error(); err != nil {
+				ctx.SetErr(err)
+			}
+			return reflect.Value{}, false
+		})
+	}
+	return d, streams
+}
+
+type shard struct {
+	ch       chan reflect.Value
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+/* dispatcher pull stream and route elements to shards */
+type dispatcher struct {
+	q         *stream
+	next      iterator
+	key       func(reflect.Value) reflect.Value
+	shards    []*shard
+	startOnce sync.Once
+	/* number of abandoned shards */
+	abandoned int32
+	mu        sync.Mutex
+	err       error
+}
+
+func (d *dispatcher) run() {
+	if d.dispatch() {
+		d.q.finish(true)
+	} else {
+		d.q.Close()
+	}
+	d.mu.Lock()
+	d.err = d.q.ctx.Err()
+	d.mu.Unlock()
+	for _, sh := range d.shards {
+		close(sh.ch)
+	}
+}
+
+/* dispatch route elements until stream is exhausted or all shards are abandoned,
+ * it runs on its own goroutine, so panics of upstream or keyFn become error of stream
+ */
+func (d *dispatcher) dispatch() (drained bool) {
+	var val reflect.Value
+	defer func() {
+		if r := recover(); r != nil {
+			d.q.ctx.SetErr(newPanicError(r, val))
+			drained = false
+		}
+	}()
+	for i := 0; atomic.LoadInt32(&d.abandoned) < int32(len(d.shards)); i++ {
+		var ok bool
+		if val, ok = d.next(); !ok {
+			return true
+		}
+		sh := d.shards[d.route(i, val)]
+		select {
+		case sh.ch <- val:
+		case <-sh.quit:
+			/* nobody reads the shard anymore */
+		}
+	}
+	return false
+}
+
+func (d *dispatcher) route(i int, val reflect.Value) int {
+	if d.key == nil {
+		return i % len(d.shards)
+	}
+	return int(hashOf(d.key(val)) % uint64(len(d.shards)))
+}
+
+/* abandon is called when a shard is closed, stream is closed once all shards are abandoned */
+func (d *dispatcher) abandon(sh *shard) {
+	sh.quitOnce.Do(func() {
+		close(sh.quit)
+		if atomic.AddInt32(&d.abandoned, 1) == int32(len(d.shards)) {
+			/* never pulled */
+			d.startOnce.Do(func() { d.q.Close() })
+		}
+	})
+}
+
+func (d *dispatcher) error() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+/* distribute run worker on shards concurrently, errors of shards caused by upstream are reported once */
+func distribute(shards []Stream, worker StreamProcessor, upstreamErr func() error) error {
+	var mu sync.Mutex
+	var errs []error
+	collect := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+	var wg sync.WaitGroup
+	for _, s := range shards {
+		wg.Add(1)
+		s.OnError(collect)
+		go func(s Stream) {
+			defer wg.Done()
+			defer s.Close()
+			defer func() {
+				if r := recover(); r != nil {
+					collect(newPanicError(r, reflect.Value{}))
+				}
+			}()
+			worker(s)
+		}(s)
+	}
+	wg.Wait()
+	var out []error
+	up := upstreamErr()
+	if up != nil {
+		out = append(out, up)
+	}
+	for _, err := range errs {
+		if up == nil || !errors.Is(err, up) {
+			out = append(out, err)
+		}
+	}
+	switch len(out) {
+	case 0:
+		return nil
+	case 1:
+		return out[0]
+	}
+	return &MultiError{Errors: out}
+}
@@ -3,5 +3,14 @@ package fp
 import "reflect"
 
 func (q *stream) Size() int {
-	return q.getValue(reflect.Value{}).Len()
+	if src, ok := q.src.(SizedSource); ok {
+		size := src.Len()
+		/* elements are kept for other terminal ops like getValue does, sources src is cut from are consumed */
+		commit(src)
+		q.finish(true)
+		return size
+	}
+	size := q.getValue(reflect.Value{}).Len()
+	q.finish(true)
+	return size
 }
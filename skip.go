@@ -6,7 +6,12 @@ import (
 )
 
 func (q *stream) Skip(size int) Stream {
-	return newStream(newCtx(q.ctx), q.expectElemTyp, q.iter, func(next iterator) iterator {
+	if src, ok := q.randomAccess(); ok {
+		n := src.Len()
+		return q.view(sliceOf(src, clamp(size, 0, n), n))
+	}
+	ctx := q.derivedCtx()
+	return newStream(ctx, q.expectElemTyp, q.upstream(ctx), func(next iterator) iterator {
 		return func() (reflect.Value, bool) {
 			for ; size > 0; size-- {
 				if _, ok := next(); !ok {
@@ -19,8 +24,11 @@ func (q *stream) Skip(size int) Stream {
 }
 
 func (q *stream) SkipWhile(fn interface{}) Stream {
-	fnval := reflect.ValueOf(fn)
-	return newStream(newCtx(q.ctx), q.expectElemTyp, q.iter, func(next iterator) iterator {
+	fn = resolvePredicate(fn, q.expectElemTyp)
+	assertPredicate("SkipWhile", fn, q.expectElemTyp)
+	pred := predicateOf(fn)
+	ctx := q.derivedCtx()
+	return newStream(ctx, q.expectElemTyp, q.upstream(ctx), func(next iterator) iterator {
 		var flag int32
 		return func() (reflect.Value, bool) {
 			if atomic.CompareAndSwapInt32(&flag, 0, 1) {
@@ -29,7 +37,7 @@ func (q *stream) SkipWhile(fn interface{}) Stream {
 					if !ok {
 						return reflect.Value{}, false
 					}
-					if !fnval.Call([]reflect.Value{val})[0].Bool() {
+					if !pred(val) {
 						return val, true
 					}
 				}
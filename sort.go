@@ -6,34 +6,63 @@ import (
 )
 
 func (q *stream) Sort() Stream {
-	var iter iterator
-	ctx := newCtx(q.ctx)
-	return newStream(ctx, q.expectElemTyp, func() (reflect.Value, bool) {
-		if iter == nil {
-			arr := q.getResult().Result()
-			v := reflect.ValueOf(arr)
-			sort.SliceStable(arr, func(i, j int) bool {
-				return q.compare(v.Index(i), v.Index(j)) < 0
-			})
-			_, iter = makeIter(ctx, reflect.ValueOf(arr))
-		}
-		return iter()
-	})
+	return q.sortWith(func(a, b reflect.Value) bool { return q.compare(a, b) < 0 })
 }
 
 func (q *stream) SortBy(fn interface{}) Stream {
-	var iter iterator
-	ctx := newCtx(q.ctx)
-	return newStream(ctx, q.expectElemTyp, func() (reflect.Value, bool) {
-		if iter == nil {
-			arr := q.getResult().Result()
-			v := reflect.ValueOf(arr)
-			fnval := reflect.ValueOf(fn)
-			sort.SliceStable(arr, func(i, j int) bool {
-				return fnval.Call([]reflect.Value{v.Index(i), v.Index(j)})[0].Bool()
-			})
-			_, iter = makeIter(ctx, reflect.ValueOf(arr))
-		}
-		return iter()
+	fn = resolveLess(fn, q.expectElemTyp)
+	assertPredicate("SortBy", fn, q.expectElemTyp, q.expectElemTyp)
+	fnval := reflect.ValueOf(fn)
+	return q.sortWith(func(a, b reflect.Value) bool {
+		return fnval.Call([]reflect.Value{a, b})[0].Bool()
 	})
 }
+
+/* sortWith sort elements of q at first access, sorted stream is random-access so Size/Skip/Take/Reverse of it take shortcuts */
+func (q *stream) sortWith(less func(a, b reflect.Value) bool) Stream {
+	src := &sortedSource{q: q, less: less}
+	return newStream(newCtx(q.ctx), q.expectElemTyp, src.Next).withSource(src)
+}
+
+/* sortedSource collect and sort elements of q when they are read, length is known without sorting if q is sized */
+type sortedSource struct {
+	q      *stream
+	less   func(a, b reflect.Value) bool
+	sorted *sliceSource
+}
+
+func (ss *sortedSource) load() *sliceSource {
+	if ss.sorted == nil {
+		arr := reflect.ValueOf(ss.q.getResult().Result())
+		sort.SliceStable(arr.Interface(), func(i, j int) bool {
+			return ss.less(arr.Index(i), arr.Index(j))
+		})
+		ss.sorted = newSliceSource(ss.q.expectElemTyp, arr).(*sliceSource)
+	}
+	return ss.sorted
+}
+
+func (ss *sortedSource) ElemType() reflect.Type { return ss.q.expectElemTyp }
+
+func (ss *sortedSource) Next() (reflect.Value, bool) { return ss.load().Next() }
+
+func (ss *sortedSource) Len() int {
+	if sized, ok := ss.q.src.(SizedSource); ok && ss.sorted == nil {
+		return sized.Len()
+	}
+	return ss.load().Len()
+}
+
+func (ss *sortedSource) At(i int) reflect.Value { return ss.load().At(i) }
+
+func (ss *sortedSource) Slice(from, to int) RandomAccessSource { return ss.load().Slice(from, to) }
+
+func (ss *sortedSource) advance(n int) {
+	if sized, ok := ss.q.src.(SizedSource); ok && ss.sorted == nil && n >= sized.Len() {
+		/* all elements are skipped, no need to sort them */
+		commit(sized)
+		ss.sorted = newSliceSource(ss.q.expectElemTyp, reflect.Zero(reflect.SliceOf(ss.q.expectElemTyp))).(*sliceSource)
+		return
+	}
+	ss.load().advance(n)
+}
@@ -2,6 +2,7 @@ package fp
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"reflect"
 )
@@ -13,21 +14,48 @@ type Source interface {
 	Next() (reflect.Value, bool)
 }
 
+// SizedSource is a Source knowing how many elements are left, Size of stream reading it directly costs O(1)
+type SizedSource interface {
+	Source
+	// Len of elements left
+	Len() int
+}
+
+// RandomAccessSource is a SizedSource whose elements left could be read by index without consuming them
+// Skip, Take and Reverse of stream reading it directly are views of it instead of iterating
+type RandomAccessSource interface {
+	SizedSource
+	// At i-th element left, 0 <= i < Len()
+	At(i int) reflect.Value
+	// Slice view elements left in [from, to) as another source, elements are not copied
+	Slice(from, to int) RandomAccessSource
+}
+
 type KVSource interface {
 	ElemType() (reflect.Type, reflect.Type)
 	Next() (reflect.Value, reflect.Value, bool)
 }
 
 func makeIter(ctx context, val reflect.Value) (reflect.Type, iterator) {
+	typ, it, _ := makeSourceIter(ctx, val)
+	return typ, it
+}
+
+/* makeSourceIter is makeIter which also return the source iterator reads from, nil if val is not a source */
+func makeSourceIter(ctx context, val reflect.Value) (reflect.Type, iterator, Source) {
 	typ := val.Type()
 	if source, ok := val.Interface().(Source); ok && source != nil {
-		return source.ElemType(), source.Next
+		closeWith(ctx, source)
+		return source.ElemType(), source.Next, source
+	}
+	if elemTyp, it, ok := seqIter(ctx, val); ok {
+		return elemTyp, it, nil
 	}
 	if isIterFunction(val) {
 		return val.Type().Out(0), func() (reflect.Value, bool) {
 			out := val.Call(nil)
 			return out[0], out[1].Bool()
-		}
+		}, nil
 	} else if isIterFunction2(val) {
 		return val.Type().Out(0), func() (reflect.Value, bool) {
 			out := val.Call(nil)
@@ -36,15 +64,15 @@ func makeIter(ctx context, val reflect.Value) (reflect.Type, iterator) {
 				return reflect.Value{}, false
 			}
 			return out[0], out[1].Bool()
-		}
+		}, nil
 	}
 	switch typ.Kind() {
 	case reflect.Slice, reflect.Array:
 		source := newSliceSource(typ.Elem(), val)
-		return source.ElemType(), source.Next
+		return source.ElemType(), source.Next, source
 	case reflect.Chan:
 		source := newChannelSource(typ.Elem(), val)
-		return source.ElemType(), source.Next
+		return source.ElemType(), source.Next, source
 	}
 	panic("not support " + typ.String())
 }
@@ -54,6 +82,15 @@ func isIterFunction(fn reflect.Value) bool {
 	return typ.Kind() == reflect.Func && typ.NumIn() == 0 && typ.NumOut() == 2 && typ.Out(1) == boolType
 }
 
+/* isSeqFunction check fn is func(yield func(n elements) bool), which is iter.Seq/iter.Seq2 */
+func isSeqFunction(typ reflect.Type, n int) bool {
+	if typ.Kind() != reflect.Func || typ.NumIn() != 1 || typ.NumOut() != 0 {
+		return false
+	}
+	yield := typ.In(0)
+	return yield.Kind() == reflect.Func && yield.NumIn() == n && yield.NumOut() == 1 && yield.Out(0) == boolType
+}
+
 func isIterFunction2(fn reflect.Value) bool {
 	typ := fn.Type()
 	return typ.Kind() == reflect.Func && typ.NumIn() == 0 && typ.NumOut() == 3 && typ.Out(1) == boolType && typ.Out(2) == errType
@@ -63,20 +100,22 @@ func isIterFunction2(fn reflect.Value) bool {
 type sliceSource struct {
 	elemType reflect.Type
 	arr      reflect.Value
-	offset   int
+	/* elements left are arr[offset:end] */
+	offset, end int
 }
 
 func newSliceSource(elemTyp reflect.Type, arr reflect.Value) Source {
 	return &sliceSource{
 		elemType: elemTyp,
 		arr:      arr,
+		end:      arr.Len(),
 	}
 }
 
 func (ss *sliceSource) ElemType() reflect.Type { return ss.elemType }
 
 func (ss *sliceSource) Next() (reflect.Value, bool) {
-	if ss.offset >= ss.arr.Len() {
+	if ss.offset >= ss.end {
 		return reflect.Value{}, false
 	}
 	offset := ss.offset
@@ -84,6 +123,102 @@ func (ss *sliceSource) Next() (reflect.Value, bool) {
 	return ss.arr.Index(offset), true
 }
 
+func (ss *sliceSource) Len() int { return ss.end - ss.offset }
+
+func (ss *sliceSource) At(i int) reflect.Value { return ss.arr.Index(ss.offset + i) }
+
+func (ss *sliceSource) Slice(from, to int) RandomAccessSource {
+	if from < 0 || from > to || to > ss.Len() {
+		panic(fmt.Sprintf("fp: slice [%d:%d] out of range with length %d", from, to, ss.Len()))
+	}
+	return &sliceSource{elemType: ss.elemType, arr: ss.arr, offset: ss.offset + from, end: ss.offset + to}
+}
+
+func (ss *sliceSource) advance(n int) { ss.offset = clamp(ss.offset+n, ss.offset, ss.end) }
+
+/* take consume next n elements as sub-slice sharing memory with source, capacity is limited to n so appending to it is safe */
+func (ss *sliceSource) take(n int) reflect.Value {
+	if ss.arr.Kind() == reflect.Array && !ss.arr.CanAddr() {
+		/* unaddressable array can't be sliced */
+		arr := reflect.New(ss.arr.Type()).Elem()
+		arr.Set(ss.arr)
+		ss.arr = arr
+	}
+	out := ss.arr.Slice3(ss.offset, ss.offset+n, ss.offset+n)
+	ss.offset += n
+	return out
+}
+
+/* advance consume next n elements of src, sources of this package skip them at once */
+func advance(src Source, n int) {
+	if s, ok := src.(interface{ advance(int) }); ok {
+		s.advance(n)
+		return
+	}
+	for ; n > 0; n-- {
+		if _, ok := src.Next(); !ok {
+			return
+		}
+	}
+}
+
+/* commit consume what collecting src would consume from sources src is cut from, elements of src are kept */
+func commit(src Source) {
+	if c, ok := src.(interface{ commit() }); ok {
+		c.commit()
+	}
+}
+
+/* viewSource is elements [from, to) of parent, reading it consumes parent like iterating parent would:
+ * elements before from are skipped once view is read, then parent moves along with view
+ */
+type viewSource struct {
+	RandomAccessSource
+	parent  Source
+	from    int
+	started bool
+	/* parent is consumed past the view already */
+	committed bool
+}
+
+/* sliceOf view [from, to) of src, reading the view consumes src */
+func sliceOf(src RandomAccessSource, from, to int) RandomAccessSource {
+	return &viewSource{RandomAccessSource: src.Slice(from, to), parent: src, from: from}
+}
+
+func (vs *viewSource) Next() (reflect.Value, bool) {
+	vs.start()
+	val, ok := vs.RandomAccessSource.Next()
+	if ok && !vs.committed {
+		advance(vs.parent, 1)
+	}
+	return val, ok
+}
+
+func (vs *viewSource) commit() {
+	if !vs.committed {
+		vs.start()
+		advance(vs.parent, vs.Len())
+		vs.committed = true
+	}
+}
+
+func (vs *viewSource) advance(n int) {
+	vs.start()
+	n = clamp(n, 0, vs.Len())
+	advance(vs.RandomAccessSource, n)
+	if !vs.committed {
+		advance(vs.parent, n)
+	}
+}
+
+func (vs *viewSource) start() {
+	if !vs.started {
+		vs.started = true
+		advance(vs.parent, vs.from)
+	}
+}
+
 /* channel stream */
 type channelSource struct {
 	elemType reflect.Type
@@ -114,17 +249,27 @@ func (cs *channelSource) Next() (reflect.Value, bool) {
 }
 
 type lineSource struct {
+	r       io.Reader
 	s       *bufio.Scanner
 	elemTyp reflect.Type
 }
 
+// NewLineSource read text line by line, r would be closed with stream if it's an io.Closer
 func NewLineSource(r io.Reader) Source {
 	return &lineSource{
+		r:       r,
 		s:       bufio.NewScanner(r),
 		elemTyp: reflect.TypeOf(""),
 	}
 }
 
+func (ls *lineSource) Close() error {
+	if c, ok := ls.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 func (ls *lineSource) ElemType() reflect.Type {
 	return ls.elemTyp
 }
@@ -2,10 +2,15 @@ package fp
 
 import (
 	"bytes"
+	gocontext "context"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -58,3 +63,248 @@ func (suite *SourceTestSuite) TestFileSource() {
 	out := StreamOfSource(s).Map(strings.ToUpper).Strings()
 	suite.Equal([]string{"FIRST", "SECOND"}, out)
 }
+
+func (suite *SourceTestSuite) TestPageSource() {
+	pages := map[string][]int{
+		"":  {1, 2},
+		"2": {},
+		"3": {3},
+	}
+	next := map[string]string{"": "2", "2": "3", "3": ""}
+	var fetched []string
+	fetch := func(token string) ([]int, string, error) {
+		fetched = append(fetched, token)
+		return pages[token], next[token], nil
+	}
+	out := StreamOfPages(fetch).Ints()
+	suite.Equal([]int{1, 2, 3}, out)
+	suite.Equal([]string{"", "2", "3"}, fetched)
+
+	fetched = nil
+	out = StreamOfPages(fetch).Take(2).Ints()
+	suite.Equal([]int{1, 2}, out)
+	suite.Equal([]string{""}, fetched)
+}
+
+func (suite *SourceTestSuite) TestPageSourcePrefetch() {
+	fetch := func(token string) ([]string, string, error) {
+		switch token {
+		case "":
+			return []string{"a", "b"}, "next", nil
+		case "next":
+			return []string{"c"}, "", nil
+		}
+		return nil, "", errors.New("bad token")
+	}
+	out := StreamOfPages(fetch, WithPrefetch()).Strings()
+	suite.Equal([]string{"a", "b", "c"}, out)
+}
+
+func (suite *SourceTestSuite) TestPageSourceError() {
+	var calls int
+	fetch := func(token string) ([]int, string, error) {
+		calls++
+		if token == "" {
+			return []int{1}, "next", nil
+		}
+		return nil, "", errors.New("page error")
+	}
+	var out []int
+	err := StreamOfPages(fetch).ToSlice(&out)
+	suite.Error(err)
+	suite.Equal([]int{1}, out)
+	suite.Equal(2, calls)
+
+	calls = 0
+	err = StreamOfPages(fetch, WithPageRetry(2, 0)).ToSlice(&out)
+	suite.Error(err)
+	suite.Equal(4, calls)
+
+	calls = 0
+	fetch = func(token string) ([]int, string, error) {
+		calls++
+		if calls == 1 {
+			return nil, "", errors.New("transient")
+		}
+		return []int{1, 2}, "", nil
+	}
+	err = StreamOfPages(fetch, WithPageRetry(1, time.Millisecond)).ToSlice(&out)
+	suite.NoError(err)
+	suite.Equal([]int{1, 2}, out)
+
+	calls = 0
+	fetch = func(token string) ([]int, string, error) {
+		calls++
+		return nil, "", fmt.Errorf("fetch: %w", gocontext.Canceled)
+	}
+	err = StreamOfPages(fetch, WithPageRetry(3, 0)).ToSlice(&out)
+	suite.True(errors.Is(err, gocontext.Canceled))
+	suite.Equal(1, calls)
+
+	calls = 0
+	permanent := errors.New("permanent")
+	fetch = func(token string) ([]int, string, error) {
+		calls++
+		if calls == 1 {
+			return nil, "", errors.New("transient")
+		}
+		return nil, "", permanent
+	}
+	err = StreamOfPages(fetch, WithPageRetry(3, 0), WithPageRetryIf(func(err error) bool { return err != permanent })).ToSlice(&out)
+	suite.Equal(permanent, err)
+	suite.Equal(2, calls)
+}
+
+type closeRecorder struct {
+	closed int
+	err    error
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+	return c.err
+}
+
+func (suite *SourceTestSuite) TestStreamHooks() {
+	var events []string
+	c := &closeRecorder{}
+	out := Using(func() (Source, io.Closer, error) {
+		return NewCounter(3), c, nil
+	}).OnComplete(func() {
+		events = append(events, "complete")
+	}).OnError(func(err error) {
+		events = append(events, "error")
+	}).Finally(func() {
+		events = append(events, "finally")
+	}).Ints()
+	suite.Equal([]int{0, 1, 2}, out)
+	suite.Equal([]string{"complete", "finally"}, events)
+	suite.Equal(1, c.closed)
+
+	events = nil
+	c = &closeRecorder{}
+	err := Using(func() (Source, io.Closer, error) {
+		return NewCounter(3), c, nil
+	}).Map(func(i int) (int, error) {
+		return i, errors.New("map error")
+	}).OnError(func(err error) {
+		events = append(events, err.Error())
+	}).Finally(func() {
+		events = append(events, "finally")
+	}).Error()
+	suite.Error(err)
+	suite.Equal([]string{"map error", "finally"}, events)
+	suite.Equal(1, c.closed)
+}
+
+func (suite *SourceTestSuite) TestStreamHooksOnAbandon() {
+	var events []string
+	c := &closeRecorder{}
+	s := Using(func() (Source, io.Closer, error) {
+		return NewCounter(3), c, nil
+	}).OnComplete(func() {
+		events = append(events, "complete")
+	}).Finally(func() {
+		events = append(events, "finally")
+	})
+	suite.Equal(0, s.First().Int())
+	suite.Equal([]string{"finally"}, events)
+	suite.Equal(1, c.closed)
+	s.Run()
+	suite.Equal([]string{"finally"}, events)
+	suite.Equal(1, c.closed)
+
+	c = &closeRecorder{}
+	s = Using(func() (Source, io.Closer, error) {
+		return NewCounter(3), c, nil
+	})
+	suite.NoError(s.Close())
+	suite.Equal(1, c.closed)
+}
+
+func (suite *SourceTestSuite) TestNilStreamHooks() {
+	var events []string
+	record := func(e string) func() { return func() { events = append(events, e) } }
+	s := newNilStream().OnComplete(record("complete")).Finally(record("finally"))
+	suite.Empty(events)
+	suite.Empty(s.Map(func(i int) int { return i }).Ints())
+	suite.Equal([]string{"complete", "finally"}, events)
+	s.Run()
+	suite.Equal([]string{"complete", "finally"}, events)
+
+	events = nil
+	suite.NoError(newNilStream().OnComplete(record("complete")).Finally(record("finally")).Close())
+	suite.Equal([]string{"finally"}, events)
+
+	events = nil
+	suite.Zero(newNilStream().OnComplete(record("complete")).GroupBy(func(i int) int { return i }).Size())
+	suite.Equal([]string{"complete"}, events)
+
+	events = nil
+	suite.Equal([]int{1}, newNilStream().OnComplete(record("complete")).Append(1).Ints())
+	suite.Equal([]string{"complete"}, events)
+
+	events = nil
+	it := newNilStream().Finally(record("finally")).Iterator()
+	suite.Empty(events)
+	suite.False(it.Next())
+	suite.Equal([]string{"finally"}, events)
+}
+
+func (suite *SourceTestSuite) TestNilStreamInheritHooks() {
+	var events []string
+	record := func(e string) func() { return func() { events = append(events, e) } }
+	c := &closeRecorder{}
+	Using(func() (Source, io.Closer, error) {
+		return StreamOf([]Stream{}).ToSource(), c, nil
+	}).OnComplete(record("complete")).Finally(record("finally")).Flatten().Run()
+	suite.Equal([]string{"complete", "finally"}, events)
+	suite.Equal(1, c.closed)
+
+	events = nil
+	Merge(newNilStream().Finally(record("input")), nil).Finally(record("merged")).Run()
+	suite.Equal([]string{"input", "merged"}, events)
+
+	events = nil
+	suite.Equal([]int{1}, Merge(newNilStream().Finally(record("input")), StreamOf([]int{1})).Ints())
+	suite.Equal([]string{"input"}, events)
+
+	events = nil
+	other := StreamOf([]int{1}).Finally(record("other"))
+	suite.Equal([]int{1}, newNilStream().Finally(record("nil")).Union(other).Ints())
+	suite.Equal([]string{"other", "nil"}, events)
+	other.Run()
+	suite.Equal([]string{"other", "nil"}, events)
+}
+
+func (suite *SourceTestSuite) TestUsingError() {
+	var closed bool
+	err := Using(func() (Source, io.Closer, error) {
+		return nil, nil, errors.New("open error")
+	}).OnError(func(error) { closed = true }).Error()
+	suite.EqualError(err, "open error")
+	suite.True(closed)
+
+	c := &closeRecorder{err: errors.New("close error")}
+	err = Using(func() (Source, io.Closer, error) {
+		return NewCounter(3), c, nil
+	}).Error()
+	suite.EqualError(err, "close error")
+}
+
+func (suite *SourceTestSuite) TestTickerSourceClosedByTake() {
+	source := NewTickerSource(time.Millisecond)
+	suite.Equal(2, StreamOf(source).Take(2).Size())
+	_, ok := source.Next()
+	suite.False(ok)
+}
+
+func (suite *SourceTestSuite) TestLineSourceClosedWithStream() {
+	c := &closeRecorder{}
+	r := struct {
+		io.Reader
+		io.Closer
+	}{Reader: bytes.NewBufferString("a\nb\n"), Closer: c}
+	suite.Equal([]string{"a", "b"}, StreamOfSource(NewLineSource(r)).Strings())
+	suite.Equal(1, c.closed)
+}
@@ -6,12 +6,20 @@ import (
 )
 
 type Stream interface {
+	seqStream
 	// Map stream to another, fn should be func(element_type) (another_type,&optional error/bool)
-	Map(fn interface{}) Stream
+	// opts can be OnError(SkipErrors/CollectErrors) to change how map errors are handled
+	Map(fn interface{}, opts ...MapOption) Stream
+	// MapOrDeadLetter map stream, elements failed to map are sent to dl and stream keeps going
+	// dl should be func(element_type, error) or func(*ElementError)
+	MapOrDeadLetter(fn interface{}, dl interface{}) Stream
 	// FlatMap stream to another, fn should be func(element_type) (slice_type,&optional error)
 	FlatMap(fn interface{}) Stream
 	// Filter stream, fn should be func(element_type) bool
 	Filter(fn interface{}) Stream
+	// FilterOrDeadLetter filter stream, fn should be func(element_type) (bool, error),
+	// elements whose fn failed are sent to dl and stream keeps going, dl is like dl of MapOrDeadLetter
+	FilterOrDeadLetter(fn interface{}, dl interface{}) Stream
 	// Reject stream, fn should be func(element_type) bool
 	Reject(fn interface{}) Stream
 	// Foreach stream element, fn should be func(element_type,optional[int])
@@ -30,6 +38,8 @@ type Stream interface {
 	LPartitionBy(fn interface{}, includeSplittor bool) Stream
 	// First value of stream
 	First() Value
+	// FirstM first value of stream as monad, monad is empty if stream is empty and failed if stream failed
+	FirstM() Monad
 	// IsEmpty stream
 	IsEmpty() bool
 	// HasSomething in stream
@@ -62,6 +72,8 @@ type Stream interface {
 	ContainsBy(fn interface{}) bool
 	// ToSource convert stream to source
 	ToSource() Source
+	// Iterator pull elements one by one without materializing stream
+	Iterator() Iterator
 	// Sub stream
 	Sub(other Stream) Stream
 	// SubBy keyfn, keyfn is func(element_type) any_type
@@ -86,8 +98,27 @@ type Stream interface {
 	Zip(other Stream, fn interface{}) Stream
 	// ZipN multiple stream , fn should be func(self_element_type,other_element_type1,other_element_type2,...) another_type
 	ZipN(fn interface{}, others ...Stream) Stream
+	// Shard split stream into n streams by keyFn func(element_type) any_type, round-robin if keyFn is nil
+	// shards are fed by one goroutine with bounded buffers, so they should be consumed concurrently
+	Shard(n int, keyFn interface{}) []Stream
+	// Distribute run worker on each shard concurrently and wait, errors of stream and workers are aggregated
+	Distribute(n int, keyFn interface{}, worker StreamProcessor) error
 	// Reverse a stream
 	Reverse() Stream
+	// Cache record elements as they are first pulled, every stream derived from it replays from the start
+	Cache() Stream
+	// CacheLimit like Cache but keep at most size latest elements
+	CacheLimit(size int) Stream
+	// SafeMode recover panics of user functions, the panic is stored as *PanicError and stops the stream
+	SafeMode() Stream
+	// OnComplete fn is called once when stream is exhausted without error
+	OnComplete(fn func()) Stream
+	// OnError fn is called once when stream failed
+	OnError(fn func(error)) Stream
+	// Finally fn is called once when stream is exhausted, failed or closed
+	Finally(fn func()) Stream
+	// Close abandon stream, close the underlying source and run hooks, it's called automatically by terminal ops
+	Close() error
 
 	// Run stream and drop value
 	Run()
@@ -112,8 +143,8 @@ type Stream interface {
 
 func StreamOf(arr interface{}) Stream {
 	ctx := newCtx(nil)
-	elemTyp, it := makeIter(ctx, reflect.ValueOf(arr))
-	return newStream(ctx, elemTyp, it)
+	elemTyp, it, src := makeSourceIter(ctx, reflect.ValueOf(arr))
+	return newStream(ctx, elemTyp, it).withSource(src)
 }
 
 func Stream0Of(arr ...interface{}) Stream {
@@ -129,7 +160,9 @@ func Stream0Of(arr ...interface{}) Stream {
 }
 
 func StreamOfSource(s Source) Stream {
-	return newStream(nil, s.ElemType(), s.Next)
+	ctx := newCtx(nil)
+	closeWith(ctx, s)
+	return newStream(ctx, s.ElemType(), s.Next).withSource(s)
 }
 
 type StreamProcessor func(Stream)
@@ -137,9 +170,15 @@ type StreamProcessor func(Stream)
 type stream struct {
 	expectElemTyp reflect.Type
 	iter          iterator
-	val           reflect.Value
-	getValOnce    sync.Once
-	ctx           context
+	/* fork create a new iterator from the start, only cached stream has it */
+	fork func(context) iterator
+	/* fused element-wise stages producing iter, nil if iter can't be extended in place */
+	fused *fusion
+	/* src is the source iter reads from directly, shortcuts use its capabilities */
+	src        Source
+	val        reflect.Value
+	getValOnce sync.Once
+	ctx        context
 }
 
 func newStream(ctx context, expTyp reflect.Type, it iterator, mws ...middleware) *stream {
@@ -149,25 +188,86 @@ func newStream(ctx context, expTyp reflect.Type, it iterator, mws ...middleware)
 	if ctx == nil {
 		ctx = newCtx(ctx)
 	}
-	if ctx.Err() != nil {
-		it = func() (reflect.Value, bool) { return reflect.Value{}, false }
-	} else {
+	build := func(it iterator) iterator {
 		for i := range mws {
 			it = mws[i](it)
 		}
+		return it
+	}
+	if ctx.Err() != nil {
+		it = func() (reflect.Value, bool) { return reflect.Value{}, false }
+	} else if ctx.Safe() {
+		it = recoverIter(ctx, it, build)
+	} else {
+		it = build(it)
 	}
 	return &stream{expectElemTyp: expTyp, iter: it, ctx: ctx}
 }
 
 /* stream to source */
 func (q *stream) ToSource() Source {
+	return q.consumer()
+}
+
+/* setIter replace iterator of q, stages fused before are dropped since they are consumed by it */
+func (q *stream) setIter(it iterator) {
+	q.iter = it
+	q.fused = nil
+	q.src = nil
+}
+
+/* withSource record src as source iter reads from, unless iter is guarded by ctx */
+func (q *stream) withSource(src Source) *stream {
+	if src != nil && q.ctx.Err() == nil && !q.ctx.Safe() {
+		q.src = src
+	}
 	return q
 }
 
+/* randomAccess return source of q if q reads from a random-access source directly */
+func (q *stream) randomAccess() (RandomAccessSource, bool) {
+	src, ok := q.src.(RandomAccessSource)
+	return src, ok
+}
+
+/* view derive stream reading from src, which is a view of source of q */
+func (q *stream) view(src RandomAccessSource) Stream {
+	return newStream(newCtx(q.ctx), q.expectElemTyp, src.Next).withSource(src)
+}
+
+/* derivedCtx is context of stream derived from q, streams derived from cached stream have their own lifecycle */
+func (q *stream) derivedCtx() context {
+	if q.fork != nil {
+		return newDetachedCtx(q.ctx)
+	}
+	return newCtx(q.ctx)
+}
+
+/* upstream iterator of derived stream whose context is ctx */
+func (q *stream) upstream(ctx context) iterator {
+	if q.fork != nil {
+		return q.fork(ctx)
+	}
+	return q.iter
+}
+
+/* consumer is the stream pulled by iterators of q, cached stream is pulled through a derived stream */
+func (q *stream) consumer() *stream {
+	if q.fork == nil {
+		return q
+	}
+	ctx := q.derivedCtx()
+	return newStream(ctx, q.expectElemTyp, q.upstream(ctx))
+}
+
 func (q *stream) ElemType() reflect.Type {
 	return q.expectElemTyp
 }
 
 func (q *stream) Next() (reflect.Value, bool) {
-	return q.iter()
+	val, ok := q.iter()
+	if !ok {
+		q.finish(true)
+	}
+	return val, ok
 }
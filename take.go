@@ -3,7 +3,11 @@ package fp
 import "reflect"
 
 func (q *stream) Take(size int) Stream {
-	return newStream(newCtx(q.ctx), q.expectElemTyp, q.iter, func(next iterator) iterator {
+	if src, ok := q.randomAccess(); ok {
+		return q.view(sliceOf(src, 0, clamp(size, 0, src.Len())))
+	}
+	ctx := q.derivedCtx()
+	return newStream(ctx, q.expectElemTyp, q.upstream(ctx), func(next iterator) iterator {
 		return func() (reflect.Value, bool) {
 			if size > 0 {
 				if val, ok := next(); ok {
@@ -17,13 +21,13 @@ func (q *stream) Take(size int) Stream {
 }
 
 func (q *stream) TakeWhile(fn interface{}) Stream {
-	fnval := reflect.ValueOf(fn)
-	return newStream(newCtx(q.ctx), q.expectElemTyp, q.iter, func(next iterator) iterator {
-		return func() (reflect.Value, bool) {
-			if val, ok := next(); ok && fnval.Call([]reflect.Value{val})[0].Bool() {
-				return val, true
-			}
-			return reflect.Value{}, false
+	fn = resolvePredicate(fn, q.expectElemTyp)
+	assertPredicate("TakeWhile", fn, q.expectElemTyp)
+	pred := predicateOf(fn)
+	return q.fuse(q.derivedCtx(), q.expectElemTyp, stage{run: func(val reflect.Value) (reflect.Value, stageResult) {
+		if pred(val) {
+			return val, emitElem
 		}
-	})
+		return val, stopStream
+	}, passThrough: true})
 }
@@ -2,23 +2,36 @@ package fp
 
 import (
 	"reflect"
+	"sync"
 	"time"
 )
 
 func NewTickerSource(interval time.Duration) *TickerSource {
 	ticker := time.NewTicker(interval)
-	return &TickerSource{ticker: ticker}
+	return &TickerSource{ticker: ticker, done: make(chan struct{})}
 }
 
 type TickerSource struct {
 	ticker *time.Ticker
+	done   chan struct{}
+	once   sync.Once
 }
 
-func (cs *TickerSource) Stop()                  { cs.ticker.Stop() }
+func (cs *TickerSource) Stop() {
+	cs.once.Do(func() {
+		cs.ticker.Stop()
+		close(cs.done)
+	})
+}
+func (cs *TickerSource) Close() error           { cs.Stop(); return nil }
 func (cs *TickerSource) ElemType() reflect.Type { return reflect.TypeOf(time.Time{}) }
 func (cs *TickerSource) Next() (reflect.Value, bool) {
-	tm, ok := <-cs.ticker.C
-	return reflect.ValueOf(tm), ok
+	select {
+	case tm := <-cs.ticker.C:
+		return reflect.ValueOf(tm), true
+	case <-cs.done:
+		return reflect.Value{}, false
+	}
 }
 
 func NewDelaySource(interval time.Duration) Source {
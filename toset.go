@@ -6,6 +6,8 @@ import (
 )
 
 func (q *stream) ToSetBy(fn interface{}) KVStream {
+	fn = resolveSelector(fn, q.expectElemTyp)
+	assertFunc("ToSetBy", fn, sig(types(q.expectElemTyp), nil), sig(types(q.expectElemTyp), nil, nil), sig(types(q.expectElemTyp), nil, nil, errType))
 	fntyp := reflect.TypeOf(fn)
 	fnval := reflect.ValueOf(fn)
 
@@ -37,10 +39,10 @@ func (q *stream) ToSetBy(fn interface{}) KVStream {
 		panic(fmt.Errorf(`bad ToSetBy function %v`, fntyp))
 	}
 
-	iter := q.iter
-	ctx := newCtx(q.ctx)
-	return newKvStream(ctx, keyTyp, valTyp, func() reflect.Value {
-		table := reflect.MakeMap(reflect.MapOf(keyTyp, valTyp))
+	ctx := q.derivedCtx()
+	iter := q.upstream(ctx)
+	return newKvStream(ctx, keyTyp, valTyp, func() kvTable {
+		table := newKVTable(keyTyp, valTyp)
 		for {
 			val, ok := iter()
 			if !ok {
@@ -51,7 +53,7 @@ func (q *stream) ToSetBy(fn interface{}) KVStream {
 				ctx.SetErr(err)
 				break
 			} else {
-				table.SetMapIndex(k, v)
+				table.Set(k, v)
 			}
 		}
 		return table
@@ -59,16 +61,17 @@ func (q *stream) ToSetBy(fn interface{}) KVStream {
 }
 
 func (q *stream) ToSet() KVStream {
-	iter := q.iter
-	return newKvStream(newCtx(q.ctx), q.expectElemTyp, boolType, func() reflect.Value {
-		table := reflect.MakeMap(reflect.MapOf(q.expectElemTyp, boolType))
+	ctx := q.derivedCtx()
+	iter := q.upstream(ctx)
+	return newKvStream(ctx, q.expectElemTyp, boolType, func() kvTable {
+		table := newKVTable(q.expectElemTyp, boolType)
 		_true := reflect.ValueOf(true)
 		for {
 			val, ok := iter()
 			if !ok {
 				break
 			}
-			table.SetMapIndex(val, _true)
+			table.Set(val, _true)
 		}
 		return table
 	})
@@ -7,7 +7,9 @@ func (q *stream) Union(other Stream) Stream {
 		return q
 	}
 	oNext := other.ToSource().Next
-	return newStream(newCtx(q.ctx), q.expectElemTyp, q.iter, func(next iterator) iterator {
+	ctx := q.derivedCtx()
+	closeWith(ctx, other)
+	return newStream(ctx, q.expectElemTyp, q.upstream(ctx), func(next iterator) iterator {
 		var otherDone bool
 		return func() (reflect.Value, bool) {
 			if !otherDone {
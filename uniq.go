@@ -4,18 +4,18 @@ import "reflect"
 
 func (q *stream) Uniq() Stream {
 	var iter iterator
-	return newStream(newCtx(q.ctx), q.expectElemTyp, func() (reflect.Value, bool) {
+	ctx := q.derivedCtx()
+	next := q.upstream(ctx)
+	return newStream(ctx, q.expectElemTyp, func() (reflect.Value, bool) {
 		if iter == nil {
-			dup := make(map[interface{}]struct{})
+			add := valueSet(q.expectElemTyp)
 			iter = func() (reflect.Value, bool) {
 				for {
-					val, ok := q.iter()
+					val, ok := next()
 					if !ok {
 						return val, false
 					}
-					key := val.Interface()
-					if _, ok := dup[key]; !ok {
-						dup[key] = struct{}{}
+					if add(val) {
 						return val, true
 					}
 				}
@@ -26,20 +26,22 @@ func (q *stream) Uniq() Stream {
 }
 
 func (q *stream) UniqBy(fn interface{}) Stream {
+	fn = resolveSelector(fn, q.expectElemTyp)
+	assertKeyFunc("UniqBy", fn, q.expectElemTyp)
 	var iter iterator
-	return newStream(newCtx(q.ctx), q.expectElemTyp, func() (reflect.Value, bool) {
+	ctx := q.derivedCtx()
+	next := q.upstream(ctx)
+	return newStream(ctx, q.expectElemTyp, func() (reflect.Value, bool) {
 		if iter == nil {
 			getKey := reflect.ValueOf(fn)
-			dup := make(map[interface{}]struct{})
+			add := valueSet(getKey.Type().Out(0))
 			iter = func() (reflect.Value, bool) {
 				for {
-					val, ok := q.iter()
+					val, ok := next()
 					if !ok {
 						return val, false
 					}
-					key := getKey.Call([]reflect.Value{val})[0].Interface()
-					if _, ok := dup[key]; !ok {
-						dup[key] = struct{}{}
+					if add(getKey.Call([]reflect.Value{val})[0]) {
 						return val, true
 					}
 				}
@@ -6,9 +6,15 @@ import (
 )
 
 var (
-	boolType   = reflect.TypeOf(true)
-	errType    = reflect.TypeOf((*error)(nil)).Elem()
-	streamType = reflect.TypeOf((*Stream)(nil)).Elem()
+	boolType    = reflect.TypeOf(true)
+	intType     = reflect.TypeOf(0)
+	int64Type   = reflect.TypeOf(int64(0))
+	float64Type = reflect.TypeOf(float64(0))
+	stringType  = reflect.TypeOf("")
+	errType     = reflect.TypeOf((*error)(nil)).Elem()
+	streamType  = reflect.TypeOf((*Stream)(nil)).Elem()
+	monadType   = reflect.TypeOf((*Monad)(nil)).Elem()
+	anyType     = reflect.TypeOf((*interface{})(nil)).Elem()
 )
 
 func NoError() func(error) bool {
@@ -191,3 +197,14 @@ func inTypes(typ reflect.Type) (out []reflect.Type) {
 	}).ToSlice(&out)
 	return
 }
+
+/* clamp n into [lo, hi] */
+func clamp(n, lo, hi int) int {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
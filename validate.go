@@ -0,0 +1,89 @@
+package fp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/* funcSig describe an expected function signature, nil type matches any type */
+type funcSig struct {
+	in, out []reflect.Type
+}
+
+func sig(in []reflect.Type, out ...reflect.Type) funcSig {
+	return funcSig{in: in, out: out}
+}
+
+func types(typ ...reflect.Type) []reflect.Type { return typ }
+
+func (s funcSig) match(typ reflect.Type) bool {
+	if typ.IsVariadic() || typ.NumIn() != len(s.in) || typ.NumOut() != len(s.out) {
+		return false
+	}
+	for i, t := range s.in {
+		if t != nil && !t.AssignableTo(typ.In(i)) {
+			return false
+		}
+	}
+	for i, t := range s.out {
+		if t != nil && !typ.Out(i).AssignableTo(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s funcSig) String() string {
+	name := func(list []reflect.Type) []string {
+		names := make([]string, len(list))
+		for i, t := range list {
+			if t == nil {
+				names[i] = "any_type"
+			} else {
+				names[i] = t.String()
+			}
+		}
+		return names
+	}
+	str := "func(" + strings.Join(name(s.in), ", ") + ")"
+	switch len(s.out) {
+	case 0:
+		return str
+	case 1:
+		return str + " " + name(s.out)[0]
+	}
+	return str + " (" + strings.Join(name(s.out), ", ") + ")"
+}
+
+/* assertFunc panic with a descriptive message unless fn matches one of sigs */
+func assertFunc(op string, fn interface{}, sigs ...funcSig) {
+	typ := reflect.TypeOf(fn)
+	if typ != nil && typ.Kind() == reflect.Func {
+		for _, s := range sigs {
+			if s.match(typ) {
+				return
+			}
+		}
+	}
+	expects := make([]string, len(sigs))
+	for i, s := range sigs {
+		expects[i] = s.String()
+	}
+	panic(fmt.Sprintf("fp: %s expects %s, got %v", op, strings.Join(expects, " or "), typ))
+}
+
+/* predicate is func(in) bool */
+func assertPredicate(op string, fn interface{}, in ...reflect.Type) {
+	assertFunc(op, fn, sig(in, boolType))
+}
+
+/* mapper is func(in) (any_type,&optional error/bool) */
+func assertMapper(op string, fn interface{}, in ...reflect.Type) {
+	assertFunc(op, fn, sig(in, nil), sig(in, nil, boolType), sig(in, nil, errType))
+}
+
+/* keyFunc is func(in) any_type */
+func assertKeyFunc(op string, fn interface{}, in ...reflect.Type) {
+	assertFunc(op, fn, sig(in, nil))
+}
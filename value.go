@@ -23,7 +23,14 @@ func (rv Value) To(dst interface{}) error {
 	if val.Kind() != reflect.Ptr {
 		return errors.New(`fp: dst must be pointer`)
 	}
-	val.Elem().Set(rv.val)
+	src := rv.val
+	if src.Kind() == reflect.Interface && !src.Type().AssignableTo(val.Elem().Type()) {
+		/* value of interface{} type, e.g. Sequence of stream whose monads are unknown until evaluated */
+		if src = src.Elem(); !src.IsValid() {
+			return nil
+		}
+	}
+	val.Elem().Set(src)
 	return nil
 }
 
@@ -51,6 +58,14 @@ func (rv Value) Err() error {
 	return nil
 }
 
+// OrDefault return result, or def if value is absent or failed
+func (rv Value) OrDefault(def interface{}) interface{} {
+	if rv.err != nil || !rv.val.IsValid() {
+		return def
+	}
+	return rv.val.Interface()
+}
+
 func (rv Value) Strings() (s []string) {
 	rv.To(&s)
 	return
@@ -1,18 +1,18 @@
 package fp
 
-import (
-	"fmt"
-	"reflect"
-)
+import "reflect"
 
 func (q *stream) Zip(other Stream, fn interface{}) Stream {
 	if isNilStream(other) {
 		return other
 	}
+	assertFunc("Zip", fn, sig(types(q.expectElemTyp, other.ToSource().ElemType()), nil))
 	fnTyp := reflect.TypeOf(fn)
 	fnVal := reflect.ValueOf(fn)
 	onext := other.ToSource().Next
-	return newStream(newCtx(q.ctx), fnTyp.Out(0), q.iter, func(next iterator) iterator {
+	ctx := q.derivedCtx()
+	closeWith(ctx, other)
+	return newStream(ctx, fnTyp.Out(0), q.upstream(ctx), func(next iterator) iterator {
 		return func() (reflect.Value, bool) {
 			if val1, ok1 := next(); ok1 {
 				if val2, ok2 := onext(); ok2 {
@@ -30,13 +30,19 @@ func (q *stream) ZipN(fn interface{}, others ...Stream) Stream {
 			return s
 		}
 	}
+	inTyps := []reflect.Type{q.expectElemTyp}
+	for _, s := range others {
+		inTyps = append(inTyps, s.ToSource().ElemType())
+	}
+	assertFunc("ZipN", fn, sig(inTyps, nil))
 	fnTyp := reflect.TypeOf(fn)
 	fnVal := reflect.ValueOf(fn)
-	if fnTyp.NumIn() != len(others)+1 {
-		panic(fmt.Sprintf("zip function must have %v input param", len(others)+1))
+	ctx := q.derivedCtx()
+	for _, s := range others {
+		closeWith(ctx, s)
 	}
 
-	return newStream(newCtx(q.ctx), fnTyp.Out(0), q.iter, func(next iterator) iterator {
+	return newStream(ctx, fnTyp.Out(0), q.upstream(ctx), func(next iterator) iterator {
 		/* build iterator list */
 		var iteratorList []iterator
 		StreamOf(others).Map(func(s Stream) iterator {